@@ -78,6 +78,17 @@ func ConfigPath(configType string) (string, error) {
 	return confPath, nil
 }
 
+// LoadConfigFile loads the config file for the given configType
+// with the provided load function.
+func LoadConfigFile(configType string, load func(file string) error) error {
+	confPath, err := ConfigPath(configType)
+	if err != nil {
+		return err
+	}
+
+	return load(confPath)
+}
+
 // GetConfigProperty returns the value for the given property.
 func GetConfigProperty(property string) string {
 	return configProperties[property]
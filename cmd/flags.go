@@ -15,6 +15,9 @@ const appVersion = "v0.0.2"
 type CmdOptions struct {
 	Page             string
 	Host, User, Pass string
+	Notify, Hook     string
+	Clipboard        string
+	DirSizes         bool
 	Version          bool
 }
 
@@ -53,6 +56,30 @@ func ParseFlags() error {
 		"",
 		"Specify a login password.",
 	)
+	fs.StringVar(
+		&cmdOptions.Notify,
+		"notify",
+		"",
+		"Notify when jobs finish or fail (comma-separated list of bell, osc9, osc777).",
+	)
+	fs.StringVar(
+		&cmdOptions.Hook,
+		"hook",
+		"",
+		"Run a command on job, mount and connection events.\nThe event name is set in RCLONETUI_EVENT, and the event information is passed as JSON to stdin.",
+	)
+	fs.StringVar(
+		&cmdOptions.Clipboard,
+		"clipboard",
+		"",
+		"Run a command to copy text to the clipboard, instead of using OSC 52.\nThe text is passed to the command's stdin.",
+	)
+	fs.BoolVar(
+		&cmdOptions.DirSizes,
+		"dir-sizes",
+		false,
+		"Automatically compute the sizes of directories in the explorer.",
+	)
 	fs.BoolVar(
 		&cmdOptions.Version,
 		"version",
@@ -63,7 +90,7 @@ func ParseFlags() error {
 	fs.Usage = func() {
 		fmt.Fprintf(
 			flag.CommandLine.Output(),
-			"rclone-tui [<flags>]\n\nConfig file is %s\n\nFlags:\n",
+			"rclone-tui [<flags>] [<command> [--json] [<args>]]\n\nConfig file is %s\n\nFlags:\n",
 			configFile,
 		)
 
@@ -80,13 +107,22 @@ func ParseFlags() error {
 
 			fmt.Fprint(flag.CommandLine.Output(), s, "\n\n")
 		})
+
+		fmt.Fprint(flag.CommandLine.Output(), headlessUsage())
 	}
 
 	fs.ParseFile(configFile)
 	fs.Parse(os.Args[1:])
 
+	if fs.NArg() > 0 {
+		cmdVersion()
+		os.Exit(RunHeadless(fs.Args()))
+	}
+
 	cmdLogin()
 	cmdPage()
+	cmdNotify()
+	cmdDirSizes()
 	cmdVersion()
 
 	return nil
@@ -135,6 +171,30 @@ func cmdPage() {
 	os.Exit(0)
 }
 
+func cmdNotify() {
+	for _, notify := range strings.Split(cmdOptions.Notify, ",") {
+		switch notify = strings.TrimSpace(notify); notify {
+		case "":
+
+		case "bell", "osc9", "osc777":
+			AddConfigProperty("notify:"+notify, "true")
+
+		default:
+			fmt.Printf("Error: %s: No such notification type\n", notify)
+			os.Exit(0)
+		}
+	}
+
+	AddConfigProperty("hook", cmdOptions.Hook)
+	AddConfigProperty("clipboard", cmdOptions.Clipboard)
+}
+
+func cmdDirSizes() {
+	if cmdOptions.DirSizes {
+		AddConfigProperty("dirsizes", "true")
+	}
+}
+
 func cmdVersion() {
 	if !cmdOptions.Version {
 		return
@@ -0,0 +1,606 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/bytefmt"
+	"github.com/darkhz/rclone-tui/rclone"
+	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
+	"github.com/jnovack/flag"
+)
+
+// Headless stores the options for a headless command.
+type Headless struct {
+	Name  string
+	Usage string
+	Help  string
+	Args  int
+
+	Run func(h *Headless, args []string) int
+
+	json  bool
+	flags *flag.FlagSet
+	opts  map[string]*string
+}
+
+// Exit codes returned by headless commands.
+const (
+	ExitSuccess = iota
+	ExitFailure
+	ExitUsage
+	ExitConnection
+)
+
+var headlessCommands = []*Headless{
+	{Name: "ls", Usage: "remote:path", Help: "List the entries within a directory.", Args: 1, Run: headlessList},
+	{Name: "cp", Usage: "remote:path... remote:dir", Help: "Copy items to a directory.", Args: 2, Run: headlessBatch},
+	{Name: "mv", Usage: "remote:path... remote:dir", Help: "Move items to a directory.", Args: 2, Run: headlessBatch},
+	{Name: "sync", Usage: "remote:path... remote:dir", Help: "Sync items to a directory.", Args: 2, Run: headlessBatch},
+	{Name: "rm", Usage: "remote:path...", Help: "Delete items.", Args: 1, Run: headlessBatch},
+	{Name: "mkdir", Usage: "remote:path", Help: "Create a directory.", Args: 1, Run: headlessMkdir},
+	{Name: "link", Usage: "remote:path", Help: "Generate a public link for an item.", Args: 1, Run: headlessLink},
+	{Name: "fsinfo", Usage: "remote:", Help: "Show information about a remote.", Args: 1, Run: headlessFsInfo},
+	{Name: "mount", Usage: "remote:path mountpoint", Help: "Mount a remote.", Args: 2, Run: headlessMount},
+	{Name: "unmount", Usage: "[mountpoint]", Help: "Unmount a mountpoint, or all mountpoints.", Run: headlessUnmount},
+	{Name: "mounts", Help: "List the mountpoints.", Run: headlessMounts},
+	{Name: "jobs", Usage: "[stop <id>]", Help: "List the running jobs, or stop a job.", Run: headlessJobs},
+	{Name: "schedules", Usage: "[run]", Help: "List the scheduled operations, or run them in the foreground.", Run: headlessSchedules},
+}
+
+// RunHeadless runs the headless command with the provided arguments,
+// and returns an exit code.
+func RunHeadless(args []string) int {
+	var command *Headless
+
+	for _, h := range headlessCommands {
+		if h.Name == args[0] {
+			command = h
+			break
+		}
+	}
+	if command == nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: No such command\n", args[0])
+		return ExitUsage
+	}
+
+	command.flags = flag.NewFlagSet(command.Name, flag.ContinueOnError)
+	command.flags.BoolVar(&command.json, "json", false, "Output in the JSON format.")
+	command.flags.Usage = func() {
+		fmt.Fprintf(
+			command.flags.Output(),
+			"rclone-tui [<flags>] %s [--json] %s\n\n%s\n",
+			command.Name, command.Usage, command.Help,
+		)
+	}
+
+	if command.Name == "mount" {
+		command.opts = map[string]*string{
+			"type":    command.flags.String("type", "", "The mount implementation to use."),
+			"options": command.flags.String("options", "", "Comma-separated mount and VFS options, for example 'AllowOther=true,CacheMode=3'."),
+		}
+	}
+
+	switch command.Name {
+	case "link":
+		command.opts = map[string]*string{
+			"expire": command.flags.String("expire", "", "The duration after which the link expires, for example '1d'."),
+		}
+
+	case "cp", "mv", "sync", "rm":
+		command.opts = map[string]*string{
+			"filter": command.flags.String("filter", "", "The name of a saved filter set to apply to directories."),
+			"preset": command.flags.String("preset", "", "The name of a saved option preset to apply."),
+		}
+	}
+
+	if err := command.flags.Parse(args[1:]); err != nil {
+		return ExitUsage
+	}
+
+	if command.flags.NArg() < command.Args {
+		command.flags.Usage()
+		return ExitUsage
+	}
+
+	if cmdOptions.Host == "" {
+		fmt.Fprintln(os.Stderr, "Error: Specify a host")
+		return ExitUsage
+	}
+
+	if _, err := rclone.Login(cmdOptions.Host, cmdOptions.User, cmdOptions.Pass); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+		return ExitConnection
+	}
+
+	return command.Run(command, command.flags.Args())
+}
+
+// headlessUsage returns the usage text for the headless commands.
+func headlessUsage() string {
+	usage := "Commands:\n"
+
+	for _, h := range headlessCommands {
+		usage += fmt.Sprintf("  %s %s\n    \t%s\n\n", h.Name, h.Usage, h.Help)
+	}
+
+	return usage + "Each command accepts the --json flag to output in the JSON format.\n"
+}
+
+// headlessList lists the entries within a directory.
+func headlessList(h *Headless, args []string) int {
+	fs, path := rcfns.SplitFS(args[0])
+
+	list, err := rcfns.ListFS("CLI", fs, path)
+	if err != nil {
+		return h.fail(err)
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		if list.Items[i].IsDir != list.Items[j].IsDir {
+			return list.Items[i].IsDir
+		}
+
+		return list.Items[i].Name < list.Items[j].Name
+	})
+
+	if h.json {
+		return h.output(list.Items)
+	}
+
+	for _, item := range list.Items {
+		name := item.Name
+		if item.IsDir {
+			name += "/"
+		}
+
+		fmt.Printf("%10s  %s  %s\n", item.ISize, item.ModifiedTime, name)
+	}
+
+	return ExitSuccess
+}
+
+// headlessBatch copies, moves, syncs or deletes items.
+func headlessBatch(h *Headless, args []string) int {
+	var job *rclone.Job
+	var opts rcfns.BatchOptions
+	var dstFs, dstRemote string
+
+	if name := *h.opts["filter"]; name != "" {
+		if err := LoadConfigFile("filters", rcfns.LoadFilters); err != nil {
+			return h.fail(err)
+		}
+
+		filter, ok := rcfns.GetFilter(name)
+		if !ok {
+			return h.fail(fmt.Errorf("%s: No such filter", name))
+		}
+
+		opts.Filter = filter
+	}
+
+	if name := *h.opts["preset"]; name != "" {
+		if err := LoadConfigFile("presets", rcfns.LoadPresets); err != nil {
+			return h.fail(err)
+		}
+
+		preset, ok := rcfns.GetPreset(name)
+		if !ok {
+			return h.fail(fmt.Errorf("%s: No such preset", name))
+		}
+
+		opts.Preset = preset
+	}
+
+	sources := args
+	if h.Name != "rm" {
+		sources = args[:len(args)-1]
+		dstFs, dstRemote = rcfns.SplitFS(args[len(args)-1])
+	}
+
+	items := make([]rcfns.ListItem, 0, len(sources))
+	for _, source := range sources {
+		fs, path := rcfns.SplitFS(source)
+
+		item, err := rcfns.StatFS(rclone.GetClientContext(), fs, path)
+		if err != nil {
+			return h.fail(err)
+		}
+
+		items = append(items, item)
+	}
+
+	events := rclone.SubscribeJobEvents(10, true)
+	defer events.Unsubscribe()
+
+	switch h.Name {
+	case "cp":
+		job = rcfns.Copy(items, dstFs, dstRemote, opts)
+
+	case "mv":
+		job = rcfns.Move(items, dstFs, dstRemote, opts)
+
+	case "sync":
+		job = rcfns.Sync(items, dstFs, dstRemote, opts)
+
+	case "rm":
+		job = rcfns.Delete(items, opts)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	go func() {
+		for {
+			select {
+			case <-interrupt:
+				job.Cancel()
+
+			case info, ok := <-events.Events():
+				if !ok {
+					return
+				}
+
+				h.progress(job, info)
+			}
+		}
+	}()
+
+	_, err := rclone.GetJobReply(job)
+
+	if h.json {
+		record := job.Record()
+		if err != nil {
+			record.Error = err.Error()
+		}
+		record.EndTime = time.Now()
+
+		if code := h.output(record); code != ExitSuccess || err != nil {
+			return ExitFailure
+		}
+
+		return ExitSuccess
+	}
+
+	if err != nil {
+		return h.fail(err)
+	}
+
+	return ExitSuccess
+}
+
+// headlessMkdir creates a directory.
+func headlessMkdir(h *Headless, args []string) int {
+	fs, path := rcfns.SplitFS(args[0])
+
+	dir, name := splitRemotePath(path)
+
+	if err := rcfns.Mkdir("CLI", fs, dir, name); err != nil {
+		return h.fail(err)
+	}
+
+	return h.output(map[string]string{"created": fs + path})
+}
+
+// headlessLink generates a public link for an item.
+func headlessLink(h *Headless, args []string) int {
+	fs, path := rcfns.SplitFS(args[0])
+
+	if err := LoadConfigFile("links", rcfns.LoadLinks); err != nil {
+		return h.fail(err)
+	}
+
+	dir, name := splitRemotePath(path)
+
+	link, err := rcfns.PublicLink(
+		"CLI", fs, dir, rcfns.ListItem{Name: name},
+		rcfns.LinkOptions{Expire: *h.opts["expire"]},
+	)
+	if err != nil {
+		return h.fail(err)
+	}
+
+	if h.json {
+		return h.output(map[string]string{"url": link})
+	}
+
+	fmt.Println(link)
+
+	return ExitSuccess
+}
+
+// headlessFsInfo shows information about a remote.
+func headlessFsInfo(h *Headless, args []string) int {
+	fs, _ := rcfns.SplitFS(args[0])
+
+	fsinfo, err := rcfns.FsInfo("CLI", fs)
+	if err != nil {
+		return h.fail(err)
+	}
+
+	if h.json {
+		return h.output(fsinfo)
+	}
+
+	fmt.Printf("Name: %s\nRoot: %s\nLog String: %s\n", fsinfo.Name, fsinfo.Root, fsinfo.String)
+	fmt.Printf("Hashes: %s\nFeatures: %s\n", strings.Join(fsinfo.Hashes, ", "), strings.Join(fsinfo.FeatureList, ", "))
+
+	return ExitSuccess
+}
+
+// headlessMount mounts a remote.
+func headlessMount(h *Headless, args []string) int {
+	mountOpt := make(map[string]interface{})
+	vfsOpt := make(map[string]interface{})
+
+	mountData := map[string]interface{}{
+		"fs":         args[0],
+		"mountPoint": args[1],
+		"mountOpt":   mountOpt,
+		"vfsOpt":     vfsOpt,
+	}
+
+	if mountType := *h.opts["type"]; mountType != "" {
+		mountData["mountType"] = mountType
+	}
+
+	for _, option := range strings.Split(*h.opts["options"], ",") {
+		var value interface{}
+
+		if option == "" {
+			continue
+		}
+
+		keyValue := strings.SplitN(option, "=", 2)
+		if len(keyValue) != 2 {
+			return h.fail(fmt.Errorf("%s: Invalid option", option))
+		}
+
+		help := rcfns.GetMountHelp(keyValue[0])
+		if help.Name == "" {
+			return h.fail(fmt.Errorf("%s: No such option", keyValue[0]))
+		}
+
+		value = keyValue[1]
+		if b, err := strconv.ParseBool(keyValue[1]); err == nil && help.ValueType == "bool" {
+			value = b
+		} else if i, err := strconv.ParseInt(keyValue[1], 10, 64); err == nil && help.ValueType == "int" {
+			value = i
+		}
+
+		switch help.OptionType {
+		case "mountOpt":
+			mountOpt[help.Name] = value
+
+		case "vfsOpt":
+			vfsOpt[help.Name] = value
+		}
+	}
+
+	if err := rcfns.CreateMount(mountData); err != nil {
+		return h.fail(err)
+	}
+
+	return h.output(map[string]string{"fs": args[0], "mountPoint": args[1]})
+}
+
+// headlessUnmount unmounts a mountpoint, or all mountpoints.
+func headlessUnmount(h *Headless, args []string) int {
+	var err error
+
+	if len(args) == 0 {
+		err = rcfns.UnmountAll()
+	} else {
+		err = rcfns.Unmount(args[0])
+	}
+	if err != nil {
+		return h.fail(err)
+	}
+
+	return ExitSuccess
+}
+
+// headlessMounts lists the mountpoints.
+func headlessMounts(h *Headless, args []string) int {
+	mountPoints, err := rcfns.GetMountPoints()
+	if err != nil {
+		return h.fail(err)
+	}
+
+	if h.json {
+		return h.output(mountPoints)
+	}
+
+	for _, point := range mountPoints {
+		fmt.Printf("%s on %s (%s)\n", point.Fs, point.MountPoint, point.MountedOn.Format("Mon 01/02 15:04"))
+	}
+
+	return ExitSuccess
+}
+
+// headlessJobs lists the running jobs, or stops a job.
+func headlessJobs(h *Headless, args []string) int {
+	if len(args) > 0 {
+		if args[0] != "stop" || len(args) < 2 {
+			h.flags.Usage()
+			return ExitUsage
+		}
+
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return h.fail(fmt.Errorf("%s: Invalid job ID", args[1]))
+		}
+
+		if err := rclone.StopJobID(id); err != nil {
+			return h.fail(err)
+		}
+
+		return ExitSuccess
+	}
+
+	jobs, err := rclone.ListJobs()
+	if err != nil {
+		return h.fail(err)
+	}
+
+	if h.json {
+		return h.output(jobs)
+	}
+
+	for _, job := range jobs {
+		state := "running"
+		if job.Finished {
+			state = "finished"
+		}
+		if job.Error != "" {
+			state = "failed: " + job.Error
+		}
+
+		fmt.Printf("%d  %s  %s  %s\n", job.ID, job.Group, job.StartTime.Format("Mon 01/02 15:04"), state)
+	}
+
+	return ExitSuccess
+}
+
+// headlessSchedules lists the scheduled operations, or runs them in the foreground.
+func headlessSchedules(h *Headless, args []string) int {
+	if err := LoadConfigFile("schedules", rcfns.LoadSchedules); err != nil {
+		return h.fail(err)
+	}
+
+	if len(args) == 0 {
+		schedules := rcfns.GetSchedules()
+
+		if h.json {
+			return h.output(schedules)
+		}
+
+		for _, schedule := range schedules {
+			nextRun := "-"
+			if !schedule.NextRun.IsZero() {
+				nextRun = schedule.NextRun.Format("Mon 01/02 15:04")
+			}
+
+			fmt.Printf("%s  next: %s  last: %s\n", schedule.Description(), nextRun, schedule.LastResult)
+		}
+
+		return ExitSuccess
+	}
+
+	if args[0] != "run" {
+		h.flags.Usage()
+		return ExitUsage
+	}
+
+	events := rclone.SubscribeJobEvents(10, true)
+	defer events.Unsubscribe()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	rcfns.StartScheduler(func(err error) {
+		h.fail(err)
+	})
+
+	for {
+		select {
+		case <-interrupt:
+			return ExitSuccess
+
+		case info := <-events.Events():
+			h.progress(nil, info)
+		}
+	}
+}
+
+// progress prints the progress of the provided job. If job is nil,
+// the progress of all jobs are printed.
+func (h *Headless) progress(job *rclone.Job, info rclone.JobInfo) {
+	if strings.HasPrefix(info.Type, "UI:") {
+		return
+	}
+
+	if job != nil && info.Group != job.Group && info.Type != "_"+job.Type {
+		return
+	}
+
+	if h.json {
+		data, err := json.Marshal(info)
+		if err == nil {
+			fmt.Fprintln(os.Stderr, string(data))
+		}
+
+		return
+	}
+
+	state := ""
+	transfer := info.CurrentTransfer
+
+	switch {
+	case info.Error != "":
+		state = "failed: " + info.Error
+
+	case info.Finished:
+		state = "done"
+
+	case transfer.Percentage > 0:
+		state = strconv.FormatInt(transfer.Percentage, 10) + "%"
+		if transfer.Speed > 0 {
+			state += " (" + bytefmt.ByteSize(uint64(transfer.Speed)) + "/s)"
+		}
+
+	case transfer.Bytes > 0:
+		state = bytefmt.ByteSize(uint64(transfer.Bytes))
+
+	default:
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "%s: %s\n", info.Description, state)
+}
+
+// output prints the data, in the JSON format if required.
+func (h *Headless) output(data interface{}) int {
+	if !h.json {
+		return ExitSuccess
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(data); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+		return ExitFailure
+	}
+
+	return ExitSuccess
+}
+
+// fail prints the error and returns the exit code for a failed command. It must
+// not be called after a result has been printed, so that only one JSON document
+// is written to the standard output.
+func (h *Headless) fail(err error) int {
+	if h.json {
+		h.output(map[string]string{"error": err.Error()})
+	} else {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+	}
+
+	return ExitFailure
+}
+
+// splitRemotePath splits the path within a remote into its directory and name.
+func splitRemotePath(remotePath string) (string, string) {
+	dir, name := path.Split(strings.TrimSuffix(remotePath, "/"))
+
+	return strings.TrimSuffix(dir, "/"), name
+}
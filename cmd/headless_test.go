@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestRunHeadlessExitCodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var command map[string]interface{}
+
+		json.NewDecoder(r.Body).Decode(&command)
+
+		switch r.URL.Path {
+		case "/core/version":
+			w.Write([]byte(`{"version": "v1.68.0"}`))
+
+		case "/job/stop":
+			if command["jobid"] != float64(1) {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"error": "job not found"}`))
+				return
+			}
+
+			w.Write([]byte(`{}`))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "couldn't find method"}`))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		host string
+		args []string
+		want int
+	}{
+		{name: "unknown command", host: server.URL, args: []string{"nope"}, want: ExitUsage},
+		{name: "missing arguments", host: server.URL, args: []string{"mkdir"}, want: ExitUsage},
+		{name: "unknown flag", host: server.URL, args: []string{"ls", "--nope", "remote:"}, want: ExitUsage},
+		{name: "no host", host: "", args: []string{"jobs"}, want: ExitUsage},
+		{name: "unreachable host", host: "http://127.0.0.1:1", args: []string{"jobs", "stop", "1"}, want: ExitConnection},
+		{name: "invalid subcommand", host: server.URL, args: []string{"jobs", "start", "1"}, want: ExitUsage},
+		{name: "invalid job ID", host: server.URL, args: []string{"jobs", "stop", "one"}, want: ExitFailure},
+		{name: "failed job stop", host: server.URL, args: []string{"jobs", "stop", "2"}, want: ExitFailure},
+		{name: "job stop", host: server.URL, args: []string{"jobs", "stop", "1"}, want: ExitSuccess},
+	}
+
+	for _, test := range tests {
+		cmdOptions.Host = test.host
+
+		var code int
+
+		captureOutput(t, func() {
+			code = RunHeadless(test.args)
+		})
+
+		if code != test.want {
+			t.Errorf("%s: exit code %d, want %d", test.name, code, test.want)
+		}
+	}
+}
+
+func TestRunHeadlessJSONError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/core/version":
+			w.Write([]byte(`{"version": "v1.68.0"}`))
+
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error": "job not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	cmdOptions.Host = server.URL
+
+	var code int
+
+	output := captureOutput(t, func() {
+		code = RunHeadless([]string{"jobs", "--json", "stop", "2"})
+	})
+	if code != ExitFailure {
+		t.Errorf("exit code %d, want %d", code, ExitFailure)
+	}
+
+	var documents []map[string]interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var document map[string]interface{}
+
+		if err := decoder.Decode(&document); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("invalid JSON output %q: %v", output, err)
+		}
+
+		documents = append(documents, document)
+	}
+
+	if len(documents) != 1 || documents[0]["error"] != "job not found" {
+		t.Errorf("output %q, want a single error document", output)
+	}
+}
+
+func TestSplitRemotePath(t *testing.T) {
+	tests := []struct {
+		path, dir, name string
+	}{
+		{path: "file.txt", dir: "", name: "file.txt"},
+		{path: "docs/file.txt", dir: "docs", name: "file.txt"},
+		{path: "docs/2024/", dir: "docs", name: "2024"},
+		{path: "a/b/c", dir: "a/b", name: "c"},
+	}
+
+	for _, test := range tests {
+		dir, name := splitRemotePath(test.path)
+		if dir != test.dir || name != test.name {
+			t.Errorf("splitRemotePath(%q) = %q, %q, want %q, %q", test.path, dir, name, test.dir, test.name)
+		}
+	}
+}
+
+// captureOutput runs fn, and returns what it writes to the standard output.
+// The standard error is discarded.
+func captureOutput(t *testing.T, fn func()) []byte {
+	t.Helper()
+
+	stdout, stderr := os.Stdout, os.Stderr
+	defer func() {
+		os.Stdout, os.Stderr = stdout, stderr
+	}()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	os.Stdout, os.Stderr = w, devNull
+
+	output := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- data
+	}()
+
+	fn()
+	w.Close()
+
+	return <-output
+}
@@ -6,9 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -19,6 +22,7 @@ type Client struct {
 	URI  *url.URL
 
 	client     *http.Client
+	transfer   *http.Client
 	user, pass string
 }
 
@@ -74,6 +78,117 @@ func (c *Client) SendRequest(command map[string]interface{}, endpoint string, ct
 	return Response{res.Body}, nil
 }
 
+// SendUpload uploads the data as a file with the provided name to the rclone host,
+// and returns a response. The parameters are sent as the request's query parameters.
+func (c *Client) SendUpload(
+	params map[string]string, endpoint, name string, data io.Reader,
+	ctx ...context.Context,
+) (Response, error) {
+	if ctx == nil {
+		ctx = append(ctx, clientContext(false))
+	}
+
+	body, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+
+	go func() {
+		part, err := w.CreateFormFile("file", name)
+		if err == nil {
+			_, err = io.Copy(part, data)
+		}
+		if err == nil {
+			err = w.Close()
+		}
+
+		pw.CloseWithError(err)
+	}()
+
+	query := url.Values{}
+	for key, value := range params {
+		query.Set(key, value)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx[0], http.MethodPost,
+		c.Host+endpoint+"?"+query.Encode(), body,
+	)
+	if err != nil {
+		body.Close()
+		return Response{}, err
+	}
+
+	req.SetBasicAuth(c.user, c.pass)
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	res, err := c.transfer.Do(req)
+	body.Close()
+	if err != nil {
+		return Response{}, err
+	}
+
+	if res.StatusCode == 401 {
+		return Response{}, fmt.Errorf("Unauthorized")
+	}
+
+	return Response{res.Body}, nil
+}
+
+// SendObjectRequest requests the provided range of the object from the rclone host.
+// If length is negative, the whole object is requested. The rclone host should be
+// started with the "--rc-serve" option to serve objects.
+func (c *Client) SendObjectRequest(fs, remote string, offset, length int64, ctx ...context.Context) (*http.Response, error) {
+	if ctx == nil {
+		ctx = append(ctx, clientContext(false))
+	}
+
+	objectURL := c.Host + "/[" + url.PathEscape(fs) + "]/"
+	for i, elem := range strings.Split(strings.TrimPrefix(remote, "/"), "/") {
+		if i > 0 {
+			objectURL += "/"
+		}
+
+		objectURL += url.PathEscape(elem)
+	}
+
+	req, err := http.NewRequestWithContext(ctx[0], http.MethodGet, objectURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(c.user, c.pass)
+	req.Header.Set("User-Agent", userAgent)
+	if length >= 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-"+strconv.FormatInt(offset+length-1, 10))
+	}
+
+	res, err := c.transfer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+		return res, nil
+
+	case http.StatusRequestedRangeNotSatisfiable:
+		res.Body.Close()
+		return nil, io.EOF
+
+	case http.StatusUnauthorized:
+		res.Body.Close()
+		return nil, fmt.Errorf("Unauthorized")
+
+	case http.StatusNotFound:
+		res.Body.Close()
+		return nil, fmt.Errorf("Object not found, the rclone host should be started with --rc-serve to preview files")
+	}
+
+	res.Body.Close()
+
+	return nil, fmt.Errorf("Cannot read object: %s", res.Status)
+}
+
 // Hostname returns the client's hostname.
 func (c *Client) Hostname() string {
 	return c.URI.Scheme + "://" + c.URI.Host
@@ -111,6 +226,7 @@ func SetupClient(host, user, pass string) error {
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		transfer: &http.Client{},
 
 		user: user,
 		pass: pass,
@@ -214,6 +330,86 @@ func SendCommand(command map[string]interface{}, endpoint string, ctx ...context
 	return client.SendRequest(command, endpoint, ctx...)
 }
 
+// UploadFile uploads the data as a file with the provided name to the remote and path.
+// This is a blocking call.
+func UploadFile(fs, remote, name string, data io.Reader, ctx ...context.Context) error {
+	var reply map[string]interface{}
+
+	client, err := GetCurrentClient()
+	if err != nil {
+		return err
+	}
+
+	response, err := client.SendUpload(
+		map[string]string{"fs": fs, "remote": remote},
+		"/operations/uploadfile", name, data, ctx...,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := response.Decode(&reply); err != nil {
+		return err
+	}
+
+	if replyErr, ok := reply["error"].(string); ok {
+		return fmt.Errorf(replyErr)
+	}
+
+	return nil
+}
+
+// ReadObject reads up to length bytes from the offset within the object at the remote
+// and path, and returns the data along with the total size of the object.
+// This is a blocking call.
+func ReadObject(fs, remote string, offset, length int64) ([]byte, int64, error) {
+	client, err := GetCurrentClient()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res, err := client.SendObjectRequest(fs, remote, offset, length)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Body.Close()
+
+	total := res.ContentLength
+
+	if res.StatusCode == http.StatusPartialContent {
+		contentRange := res.Header.Get("Content-Range")
+		if i := strings.LastIndex(contentRange, "/"); i >= 0 {
+			if size, err := strconv.ParseInt(contentRange[i+1:], 10, 64); err == nil {
+				total = size
+			}
+		}
+	} else if offset > 0 {
+		if _, err := io.CopyN(io.Discard, res.Body, offset); err != nil {
+			return nil, total, err
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, length))
+
+	return data, total, err
+}
+
+// OpenObject opens the object at the remote and path for reading, and returns
+// the object's contents along with its size. This is a blocking call.
+func OpenObject(ctx context.Context, fs, remote string) (io.ReadCloser, int64, error) {
+	client, err := GetCurrentClient()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res, err := client.SendObjectRequest(fs, remote, 0, -1, ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res.Body, res.ContentLength, nil
+}
+
 // SendCommandAsync asynchronously sends a command to rclone and returns the job information
 // for the running command.
 func SendCommandAsync(
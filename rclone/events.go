@@ -0,0 +1,169 @@
+package rclone
+
+import (
+	"strconv"
+	"sync"
+)
+
+// JobEvents stores a subscriber's stream of job events.
+type JobEvents struct {
+	events chan JobInfo
+	notify chan struct{}
+	done   chan struct{}
+
+	size     int
+	coalesce bool
+
+	queue []JobInfo
+	lock  sync.Mutex
+	once  sync.Once
+}
+
+var (
+	subscribers    map[*JobEvents]struct{}
+	subscriberLock sync.Mutex
+)
+
+// SubscribeJobEvents returns a new stream of job events. If coalesce is set, only
+// the latest progress event for each job is kept until it is received, otherwise
+// up to size progress events are buffered. Events which indicate that a job has
+// finished or failed are never dropped.
+func SubscribeJobEvents(size int, coalesce bool) *JobEvents {
+	subscriberLock.Lock()
+	defer subscriberLock.Unlock()
+
+	if subscribers == nil {
+		subscribers = make(map[*JobEvents]struct{})
+	}
+
+	j := &JobEvents{
+		events: make(chan JobInfo),
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+
+		size:     size,
+		coalesce: coalesce,
+	}
+
+	subscribers[j] = struct{}{}
+
+	go j.deliver()
+
+	return j
+}
+
+// Events returns the channel on which job events are received.
+func (j *JobEvents) Events() <-chan JobInfo {
+	return j.events
+}
+
+// Unsubscribe stops receiving job events, and closes the event channel.
+func (j *JobEvents) Unsubscribe() {
+	subscriberLock.Lock()
+	delete(subscribers, j)
+	subscriberLock.Unlock()
+
+	j.once.Do(func() {
+		close(j.done)
+	})
+}
+
+// IsTerminal returns whether the job event indicates that the job has finished or failed.
+func (info JobInfo) IsTerminal() bool {
+	return info.Finished || info.Error != ""
+}
+
+// PublishJobInfo sends the information of a job which is run by rclone-tui
+// instead of the rclone host, for example a local file transfer, to all subscribers.
+func PublishJobInfo(info JobInfo) {
+	publishJobEvent(info)
+}
+
+// publishJobEvent sends the job event to all subscribers. This does not block.
+func publishJobEvent(info JobInfo) {
+	subscriberLock.Lock()
+	defer subscriberLock.Unlock()
+
+	for j := range subscribers {
+		j.push(info)
+	}
+}
+
+// push queues the job event for delivery.
+func (j *JobEvents) push(info JobInfo) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if !info.IsTerminal() {
+		var progress int
+
+		key := jobEventKey(info)
+
+		for i, queued := range j.queue {
+			if queued.IsTerminal() {
+				continue
+			}
+
+			if j.coalesce && jobEventKey(queued) == key {
+				j.queue[i] = info
+				goto Notify
+			}
+
+			progress++
+		}
+
+		if !j.coalesce && progress >= j.size {
+			for i, queued := range j.queue {
+				if !queued.IsTerminal() {
+					j.queue = append(j.queue[:i], j.queue[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+
+	j.queue = append(j.queue, info)
+
+Notify:
+	select {
+	case j.notify <- struct{}{}:
+
+	default:
+	}
+}
+
+// deliver sends the queued job events to the subscriber.
+func (j *JobEvents) deliver() {
+	defer close(j.events)
+
+	for {
+		j.lock.Lock()
+		if len(j.queue) == 0 {
+			j.lock.Unlock()
+
+			select {
+			case <-j.notify:
+				continue
+
+			case <-j.done:
+				return
+			}
+		}
+
+		info := j.queue[0]
+		j.queue = j.queue[1:]
+		j.lock.Unlock()
+
+		select {
+		case j.events <- info:
+
+		case <-j.done:
+			return
+		}
+	}
+}
+
+// jobEventKey returns a key which identifies the job the event belongs to.
+func jobEventKey(info JobInfo) string {
+	return info.Type + "|" + info.Group + "|" + strconv.FormatInt(info.ID, 10)
+}
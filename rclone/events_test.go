@@ -0,0 +1,123 @@
+package rclone
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestJobEventsKeepTerminal(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		coalesce bool
+		jobs     int
+		progress int
+	}{
+		{name: "coalesced", size: 1, coalesce: true, jobs: 5, progress: 100},
+		{name: "buffered", size: 3, coalesce: false, jobs: 5, progress: 100},
+		{name: "unbuffered", size: 0, coalesce: false, jobs: 20, progress: 10},
+	}
+
+	for _, test := range tests {
+		events := SubscribeJobEvents(test.size, test.coalesce)
+
+		var terminal []string
+
+		for id := 0; id < test.jobs; id++ {
+			for i := 0; i < test.progress; i++ {
+				publishJobEvent(JobInfo{ID: int64(id), Type: "Copy"})
+			}
+
+			info := JobInfo{ID: int64(id), Type: "Copy", Finished: true}
+			if id%2 == 1 {
+				info = JobInfo{ID: int64(id), Type: "Copy", Error: "failed"}
+			}
+
+			publishJobEvent(info)
+			terminal = append(terminal, jobEventKey(info))
+		}
+
+		var received []string
+		var progress int
+
+	Receive:
+		for {
+			select {
+			case info := <-events.Events():
+				if !info.IsTerminal() {
+					progress++
+					continue
+				}
+
+				received = append(received, jobEventKey(info))
+
+			case <-time.After(100 * time.Millisecond):
+				break Receive
+			}
+		}
+
+		events.Unsubscribe()
+
+		if len(received) != len(terminal) {
+			t.Errorf("%s: received %d terminal events, want %d", test.name, len(received), len(terminal))
+			continue
+		}
+
+		for i := range terminal {
+			if received[i] != terminal[i] {
+				t.Errorf("%s: terminal event %d is %s, want %s", test.name, i, received[i], terminal[i])
+			}
+		}
+
+		// The latest progress event is always queued, and the delivering
+		// goroutine may hold one more while the subscriber is not receiving.
+		limit := test.size + 1
+		if test.size == 0 {
+			limit = 2
+		}
+		if test.coalesce {
+			limit = test.jobs + 1
+		}
+		if progress > limit {
+			t.Errorf("%s: received %d progress events, want at most %d", test.name, progress, limit)
+		}
+	}
+}
+
+func TestSendJobUpdateKeepsTerminal(t *testing.T) {
+	tests := []struct {
+		name    string
+		queued  int
+		info    JobInfo
+		wantLen int
+	}{
+		{name: "progress on empty", queued: 0, info: JobInfo{}, wantLen: 1},
+		{name: "progress on full", queued: 10, info: JobInfo{}, wantLen: 10},
+		{name: "finished on full", queued: 10, info: JobInfo{Finished: true}, wantLen: 10},
+		{name: "error on full", queued: 10, info: JobInfo{Error: "failed"}, wantLen: 10},
+	}
+
+	for _, test := range tests {
+		job := NewJob("Copy", "Copying", 1)
+
+		for i := 0; i < test.queued; i++ {
+			job.Updates <- JobInfo{Description: strconv.Itoa(i)}
+		}
+
+		sendJobUpdate(job, test.info)
+
+		if len(job.Updates) != test.wantLen {
+			t.Errorf("%s: %d updates queued, want %d", test.name, len(job.Updates), test.wantLen)
+		}
+
+		var last JobInfo
+		for len(job.Updates) > 0 {
+			last = <-job.Updates
+		}
+
+		if test.info.IsTerminal() && !last.IsTerminal() {
+			t.Errorf("%s: the terminal update was dropped", test.name)
+		}
+	}
+}
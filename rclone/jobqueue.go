@@ -18,6 +18,7 @@ type Job struct {
 
 	Type        string
 	Description string
+	StartTime   time.Time
 	Updates     chan JobInfo
 	Cancel      context.CancelFunc
 
@@ -61,9 +62,8 @@ type TransferStat struct {
 var (
 	jobQueue sync.Map
 
-	jobLock     sync.Mutex
-	jobTotal    int64
-	JobInfoChan chan JobInfo
+	jobLock  sync.Mutex
+	jobTotal int64
 )
 
 // NewJob returns a job with the provided type, description, and optional group.
@@ -85,6 +85,7 @@ func NewJob(jobType, jobDesc string, jobId int64, jobGroup ...string) *Job {
 		Type:        jobType,
 		Updates:     jobChan,
 		Description: jobDesc,
+		StartTime:   time.Now(),
 		Cancel:      cancel,
 	}
 
@@ -141,6 +142,41 @@ MonitorStatus:
 	return info, err
 }
 
+// ListJobs returns the status of all the jobs on the rclone host.
+func ListJobs() ([]JobInfo, error) {
+	var list struct {
+		JobIDs []int64 `json:"jobids"`
+	}
+
+	response, err := SendCommand(map[string]interface{}{}, "/job/list")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := response.Decode(&list); err != nil {
+		return nil, err
+	}
+
+	jobs := make([]JobInfo, 0, len(list.JobIDs))
+
+	for _, id := range list.JobIDs {
+		var jobInfo JobInfo
+
+		response, err := SendCommand(map[string]interface{}{"jobid": id}, "/job/status")
+		if err != nil {
+			return nil, err
+		}
+
+		if err := response.Decode(&jobInfo); err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, jobInfo)
+	}
+
+	return jobs, nil
+}
+
 // GetJobQueue returns the job queue.
 func GetJobQueue() *sync.Map {
 	return &jobQueue
@@ -168,20 +204,10 @@ func GetLatestJob(jobType string) (*Job, error) {
 	return jobMap[jobId], nil
 }
 
-// JobInfoStatus returns the job stats update channel.
-func JobInfoStatus() chan JobInfo {
-	jobLock.Lock()
-	defer jobLock.Unlock()
-
-	if JobInfoChan == nil {
-		JobInfoChan = make(chan JobInfo, 100)
-	}
-
-	return JobInfoChan
-}
-
 // MonitorJob monitors the provided job, and if nostop is not set, it will
-// automatically stop monitoring the job.
+// automatically stop monitoring the job. Only progress events are published,
+// the finished or failed event is published once by StopJob, which has to be
+// called for the job if nostop is set.
 //
 //gocyclo:ignore
 func MonitorJob(job *Job, nostop ...struct{}) {
@@ -240,25 +266,18 @@ func MonitorJob(job *Job, nostop ...struct{}) {
 		jobInfo.Transfers.Stats = nil
 
 	SendInfo:
-		select {
-		case job.Updates <- jobInfo:
-			select {
-			case JobInfoStatus() <- jobInfo:
+		sendJobUpdate(job, jobInfo)
 
-			default:
+		if jobInfo.IsTerminal() {
+			if nostop == nil {
+				StopJob(job, jobInfo.Error)
 			}
 
-			if jobInfo.Error != "" || jobInfo.Finished == true {
-				if nostop == nil {
-					StopJob(job, jobInfo.Error)
-				}
-
-				return
-			}
-
-		default:
+			return
 		}
 
+		publishJobEvent(jobInfo)
+
 		select {
 		case <-t.C:
 
@@ -269,7 +288,8 @@ func MonitorJob(job *Job, nostop ...struct{}) {
 	}
 }
 
-// StopJob stops the provided job.
+// StopJob stops the provided job, and notifies any listeners
+// waiting on the job's updates.
 func StopJob(job *Job, errors string, force ...struct{}) {
 	command := map[string]interface{}{
 		"jobid": job.ID,
@@ -293,6 +313,8 @@ func StopJob(job *Job, errors string, force ...struct{}) {
 	}
 
 JobFinished:
+	addJobHistory(job, errors)
+
 	jobFinished := JobInfo{
 		ID:          job.ID,
 		Type:        job.Type,
@@ -305,11 +327,56 @@ JobFinished:
 		RefreshItems: job.RefreshItems,
 	}
 
+	publishJobEvent(jobFinished)
+	sendJobUpdate(job, jobFinished)
+}
+
+// sendJobUpdate sends the job information to the job's updates channel without
+// blocking. If the channel is full, progress updates are dropped, and the oldest
+// queued update is discarded to make room for a finished or failed update.
+func sendJobUpdate(job *Job, info JobInfo) {
+	select {
+	case job.Updates <- info:
+		return
+
+	default:
+	}
+
+	if !info.IsTerminal() {
+		return
+	}
+
 	select {
-	case JobInfoStatus() <- jobFinished:
+	case <-job.Updates:
 
 	default:
 	}
+
+	select {
+	case job.Updates <- info:
+
+	default:
+	}
+}
+
+// StopJobID stops the job with the provided ID on the rclone host.
+func StopJobID(id int64) error {
+	var reply map[string]interface{}
+
+	response, err := SendCommand(map[string]interface{}{"jobid": id}, "/job/stop")
+	if err != nil {
+		return err
+	}
+
+	if err := response.Decode(&reply); err != nil {
+		return err
+	}
+
+	if jobError, ok := reply["error"].(string); ok {
+		return fmt.Errorf(jobError)
+	}
+
+	return nil
 }
 
 // StopJobGroup stops all jobs associated with the group.
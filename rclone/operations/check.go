@@ -0,0 +1,115 @@
+package rclone
+
+import (
+	"strings"
+
+	"github.com/darkhz/rclone-tui/rclone"
+)
+
+// The results of comparing an item between the source and the destination.
+const (
+	CheckIdentical    = "identical"
+	CheckDiffer       = "differ"
+	CheckMissingOnSrc = "missingOnSrc"
+	CheckMissingOnDst = "missingOnDst"
+	CheckError        = "error"
+)
+
+// CheckResult stores the result of comparing the source and the destination.
+type CheckResult struct {
+	Success  bool
+	Status   string
+	HashType string
+
+	Counts  map[string]int
+	Entries map[string]string
+}
+
+// Check compares the files within the source and the destination. If download is set,
+// the files are compared by downloading them, and if oneway is set, only the files
+// within the source are checked. The results for each file are grouped by the top-level
+// entry within the directories, and an entry which has files with different results
+// is marked as differing.
+func Check(id, srcFs, dstFs string, download, oneway bool) (CheckResult, error) {
+	result := CheckResult{
+		Counts:  make(map[string]int),
+		Entries: make(map[string]string),
+	}
+
+	command := map[string]interface{}{
+		"srcFs":    srcFs,
+		"dstFs":    dstFs,
+		"download": download,
+		"oneway":   oneway,
+		"combined": true,
+		"match":    true,
+	}
+
+	job, err := rclone.SendCommandAsync("UI:Explorer:"+id, "Comparing "+srcFs+" and "+dstFs, command, "/operations/check")
+	if err != nil {
+		return CheckResult{}, err
+	}
+
+	jobInfo, err := rclone.GetJobReply(job)
+	if err != nil {
+		return CheckResult{}, err
+	}
+
+	result.Success, _ = jobInfo.Output["success"].(bool)
+	result.Status, _ = jobInfo.Output["status"].(string)
+	result.HashType, _ = jobInfo.Output["hashType"].(string)
+
+	combined, _ := jobInfo.Output["combined"].([]interface{})
+
+	for _, c := range combined {
+		var status string
+
+		line, ok := c.(string)
+		if !ok || len(line) < 3 {
+			continue
+		}
+
+		switch line[0] {
+		case '=':
+			status = CheckIdentical
+
+		case '*':
+			status = CheckDiffer
+
+		case '-':
+			status = CheckMissingOnSrc
+
+		case '+':
+			status = CheckMissingOnDst
+
+		case '!':
+			status = CheckError
+
+		default:
+			continue
+		}
+
+		result.Counts[status]++
+
+		name := line[2:]
+		if i := strings.Index(name, "/"); i >= 0 {
+			name = name[:i]
+		}
+
+		if entry, ok := result.Entries[name]; ok && entry != status {
+			if entry != CheckError {
+				result.Entries[name] = CheckDiffer
+			}
+
+			if status == CheckError {
+				result.Entries[name] = CheckError
+			}
+
+			continue
+		}
+
+		result.Entries[name] = status
+	}
+
+	return result, nil
+}
@@ -0,0 +1,119 @@
+package rclone
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ConflictAction is the action taken for an item which already exists
+// within the destination of a copy or move operation.
+type ConflictAction int
+
+// The actions which can be taken for conflicting items.
+const (
+	ConflictOverwrite ConflictAction = iota
+	ConflictSkip
+	ConflictKeepNewer
+	ConflictKeepBoth
+)
+
+// Conflict stores an item which already exists within the destination.
+// Name is the name which the item is transferred with if both items are kept.
+type Conflict struct {
+	Item, Existing ListItem
+
+	Name   string
+	Action ConflictAction
+}
+
+// FindConflicts lists the destination, and returns the items which
+// already exist within it.
+func FindConflicts(ctx context.Context, items []ListItem, dstFs, dstRemote string) ([]Conflict, error) {
+	var conflicts []Conflict
+	var list List
+
+	if IsLocal(dstFs) {
+		localList, err := ListLocal(dstRemote)
+		if err != nil {
+			return nil, err
+		}
+
+		list = localList
+	} else {
+		items, err := listDir(ctx, dstFs, dstRemote)
+		if err != nil {
+			return nil, err
+		}
+
+		list.Items = items
+	}
+
+	existing := make(map[string]ListItem, len(list.Items))
+	for _, item := range list.Items {
+		existing[item.Name] = item
+	}
+
+	names := make(map[string]struct{}, len(existing))
+	for name := range existing {
+		names[name] = struct{}{}
+	}
+
+	for _, item := range items {
+		existingItem, ok := existing[item.Name]
+		if !ok {
+			continue
+		}
+
+		name := conflictName(item, names)
+		names[name] = struct{}{}
+
+		conflicts = append(conflicts, Conflict{
+			Item:     item,
+			Existing: existingItem,
+			Name:     name,
+		})
+	}
+
+	return conflicts, nil
+}
+
+// skip returns whether the item should not be transferred. Files are skipped
+// if the skip action is set, or if the existing file is newer and only newer
+// files are kept. For directories, the action is applied to the files within them.
+func (c Conflict) skip() bool {
+	if c.Item.IsDir {
+		return false
+	}
+
+	switch c.Action {
+	case ConflictSkip:
+		return true
+
+	case ConflictKeepNewer:
+		return c.Existing.ModifiedTimeUnix >= c.Item.ModifiedTimeUnix
+	}
+
+	return false
+}
+
+// conflictName returns a name for the item which is not present within names,
+// by adding a number to the name, for example "file (1).txt".
+func conflictName(item ListItem, names map[string]struct{}) string {
+	base, ext := item.Name, ""
+	if !item.IsDir {
+		ext = filepath.Ext(item.Name)
+		base = strings.TrimSuffix(item.Name, ext)
+	}
+	if base == "" {
+		base, ext = item.Name, ""
+	}
+
+	for i := 1; ; i++ {
+		name := base + " (" + strconv.Itoa(i) + ")" + ext
+		if _, ok := names[name]; !ok {
+			return name
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package rclone
+
+import "testing"
+
+func TestConflictName(t *testing.T) {
+	tests := []struct {
+		name  string
+		isDir bool
+		names []string
+		want  string
+	}{
+		{name: "file.txt", names: []string{"file.txt"}, want: "file (1).txt"},
+		{name: "file.txt", names: []string{"file.txt", "file (1).txt", "file (2).txt"}, want: "file (3).txt"},
+		{name: "archive.tar.gz", names: []string{"archive.tar.gz"}, want: "archive.tar (1).gz"},
+		{name: "README", names: []string{"README"}, want: "README (1)"},
+		{name: ".bashrc", names: []string{".bashrc"}, want: ".bashrc (1)"},
+		{name: "photos.2024", isDir: true, names: []string{"photos.2024"}, want: "photos.2024 (1)"},
+		{name: "docs", isDir: true, names: []string{"docs", "docs (1)"}, want: "docs (2)"},
+	}
+
+	for _, test := range tests {
+		names := make(map[string]struct{})
+		for _, name := range test.names {
+			names[name] = struct{}{}
+		}
+
+		item := ListItem{Name: test.name, IsDir: test.isDir}
+		if got := conflictName(item, names); got != test.want {
+			t.Errorf("conflictName(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
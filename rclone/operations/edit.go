@@ -0,0 +1,64 @@
+package rclone
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"code.cloudfoundry.org/bytefmt"
+	"github.com/darkhz/rclone-tui/rclone"
+)
+
+// MaxEditSize is the maximum size of a file which can be edited.
+const MaxEditSize = 1024 * 1024
+
+// ReadFile reads the contents of the file, and returns the contents along with
+// the file's current information, which can be used to detect modifications.
+func ReadFile(item ListItem) ([]byte, ListItem, error) {
+	current, err := StatFS(rclone.GetClientContext(), item.FS, item.Path)
+	if err != nil {
+		return nil, ListItem{}, err
+	}
+
+	if current.IsDir {
+		return nil, ListItem{}, fmt.Errorf("%s is a directory", item.Name)
+	}
+
+	if current.Size > MaxEditSize {
+		return nil, ListItem{}, fmt.Errorf(
+			"%s is too large to edit (%s, maximum is %s)", item.Name,
+			bytefmt.ByteSize(uint64(current.Size)), bytefmt.ByteSize(MaxEditSize),
+		)
+	}
+
+	if current.Size == 0 {
+		return []byte{}, current, nil
+	}
+
+	data, _, err := rclone.ReadObject(item.FS, item.Path, 0, MaxEditSize)
+	if err != nil && err != io.EOF {
+		return nil, ListItem{}, err
+	}
+
+	return data, current, nil
+}
+
+// FileModified returns whether the file has been modified since its information was retrieved.
+func FileModified(original ListItem) (bool, error) {
+	current, err := StatFS(rclone.GetClientContext(), original.FS, original.Path)
+	if err != nil {
+		return false, err
+	}
+
+	return current.Size != original.Size || current.ModifiedTimeUnix != original.ModifiedTimeUnix, nil
+}
+
+// WriteFile uploads the contents to the file, and returns the file's updated information.
+func WriteFile(item ListItem, data []byte) (ListItem, error) {
+	if err := rclone.UploadFile(item.FS, filepath.Dir(item.Path), item.Name, bytes.NewReader(data)); err != nil {
+		return ListItem{}, err
+	}
+
+	return StatFS(rclone.GetClientContext(), item.FS, item.Path)
+}
@@ -3,8 +3,11 @@ package rclone
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"path"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"code.cloudfoundry.org/bytefmt"
 	"github.com/darkhz/rclone-tui/rclone"
@@ -29,11 +32,13 @@ func Mkdir(id, fs, remote, name string) error {
 
 	jobInfo, err := rclone.GetJobReply(job)
 	if err != nil {
+		rclone.StopJob(job, jobInfo.Error)
 		return err
 	}
 
 	listItem, err := stat(rclone.GetClientContext(), fs, command["remote"].(string))
 	if err != nil {
+		rclone.StopJob(job, err.Error())
 		return err
 	}
 
@@ -46,14 +51,33 @@ func Mkdir(id, fs, remote, name string) error {
 	return nil
 }
 
-// PublicLink returns a public link for the provided item.
-func PublicLink(id, fs, remote string, item ListItem) (string, error) {
+// PublicLink returns a public link for the provided item. If the link options
+// are provided, the link expires after the provided duration, or the existing
+// link is removed if Unlink is set. Generated links are recorded, and can be
+// retrieved with GetLinks.
+func PublicLink(id, fs, remote string, item ListItem, opts ...LinkOptions) (string, error) {
+	var linkOpts LinkOptions
+
+	if opts != nil {
+		linkOpts = opts[0]
+	}
+
+	itemPath := filepath.Join(remote, item.Name)
 	command := map[string]interface{}{
 		"fs":     fs,
-		"remote": filepath.Join(remote, item.Name),
+		"remote": itemPath,
 	}
 
-	job, err := rclone.SendCommandAsync("UI:Explorer:"+id, "Generating public link", command, "/operations/publiclink")
+	desc := "Generating public link"
+	if linkOpts.Expire != "" {
+		command["expire"] = linkOpts.Expire
+	}
+	if linkOpts.Unlink {
+		command["unlink"] = true
+		desc = "Removing public link"
+	}
+
+	job, err := rclone.SendCommandAsync("UI:Explorer:"+id, desc, command, "/operations/publiclink")
 	if err != nil {
 		return "", err
 	}
@@ -63,52 +87,159 @@ func PublicLink(id, fs, remote string, item ListItem) (string, error) {
 		return "", err
 	}
 
+	if linkOpts.Unlink {
+		return "", removeLink(fs, itemPath)
+	}
+
 	url, ok := jobInfo.Output["url"]
 	if !ok || ok && url == nil {
 		return "", fmt.Errorf("Public link could not be generated")
 	}
 
-	return url.(string), nil
+	link := Link{
+		FS:      fs,
+		Path:    itemPath,
+		URL:     url.(string),
+		Expire:  linkOpts.Expire,
+		Created: time.Now().Unix(),
+	}
+
+	return link.URL, saveLink(link)
+}
+
+// CopyURL downloads the URL to the remote and path with the provided name. If
+// name is empty, the name is determined from the URL.
+func CopyURL(fs, remote, fileURL, name string) (*rclone.Job, error) {
+	command := map[string]interface{}{
+		"url": fileURL,
+	}
+
+	if name == "" {
+		u, err := url.Parse(fileURL)
+		if err != nil {
+			return nil, err
+		}
+
+		name = path.Base(u.Path)
+		if name == "." || name == "/" {
+			return nil, fmt.Errorf("Cannot determine the file name from %s", fileURL)
+		}
+
+		command["fs"] = fs + remote
+		command["remote"] = ""
+		command["autoFilename"] = true
+	} else {
+		command["fs"] = fs
+		command["remote"] = filepath.Join(remote, name)
+	}
+
+	return startJob(
+		"CopyURL", "Downloading "+fileURL+" -> "+fs+filepath.Join(remote, name), "/operations/copyurl", command,
+		func(jobInfo rclone.JobInfo) []ListItem {
+			if jobInfo.Error != "" {
+				return nil
+			}
+
+			listItem, err := stat(rclone.GetClientContext(), fs, filepath.Join(remote, name))
+			if err != nil || listItem.Name == "" {
+				return nil
+			}
+
+			listItem = appendItemDetails(listItem, fs)
+			listItem.RefreshAddItem = true
+
+			return []ListItem{listItem}
+		},
+	), nil
+}
+
+// BatchOptions stores the filter rules, the rclone options and the actions
+// for items which already exist within the destination, for a batch operation.
+// Tier is the storage tier which items are moved to by a SetTier operation.
+type BatchOptions struct {
+	Filter    Filter
+	Preset    Preset
+	Conflicts map[ListItem]Conflict
+	Tier      string
 }
 
 // Copy copies a list of items to the destination remote and path.
-func Copy(items []ListItem, dstFs, dstRemote string) {
-	BatchOperation(
+// Items are uploaded from or downloaded to the local filesystem if
+// either the items or the destination are within it.
+func Copy(items []ListItem, dstFs, dstRemote string, opts ...BatchOptions) *rclone.Job {
+	if IsLocal(dstFs) || (len(items) > 0 && IsLocal(items[0].FS)) {
+		return LocalCopy(items, dstFs, dstRemote)
+	}
+
+	return BatchOperation(
 		"Copy", "Copying", dstFs, dstRemote,
-		[]string{"/sync/copy", "/operations/copyfile"}, items,
+		[]string{"/sync/copy", "/operations/copyfile"}, items, batchOptions(opts),
 	)
 }
 
 // Move moves a list of items to the destination remote and path.
-func Move(items []ListItem, dstFs, dstRemote string) {
-	BatchOperation(
+func Move(items []ListItem, dstFs, dstRemote string, opts ...BatchOptions) *rclone.Job {
+	return BatchOperation(
 		"Move", "Moving", dstFs, dstRemote,
-		[]string{"/sync/move", "/operations/movefile"}, items,
+		[]string{"/sync/move", "/operations/movefile"}, items, batchOptions(opts),
 	)
 }
 
-// Delete deletes a list of items from the remote.
-func Delete(items []ListItem) {
-	BatchOperation(
+// Sync syncs a list of items to the destination remote and path.
+func Sync(items []ListItem, dstFs, dstRemote string, opts ...BatchOptions) *rclone.Job {
+	return BatchOperation(
+		"Sync", "Syncing", dstFs, dstRemote,
+		[]string{"/sync/sync", "/operations/copyfile"}, items, batchOptions(opts),
+	)
+}
+
+// Delete deletes a list of items from the remote. If a filter is provided,
+// only the files within directories which match the filter are deleted.
+func Delete(items []ListItem, opts ...BatchOptions) *rclone.Job {
+	endpoints := []string{"/operations/purge", "/operations/deletefile"}
+	if !batchOptions(opts).Filter.IsEmpty() {
+		endpoints[0] = "/operations/delete"
+	}
+
+	return BatchOperation(
 		"Delete", "Deleting", "", "",
-		[]string{"/operations/purge", "/operations/deletefile"}, items,
+		endpoints, items, batchOptions(opts),
 	)
 }
 
-// BatchOperation starts a batch job on a list of items.
+// Rename renames a list of items within their directories. The new name
+// for each item is looked up from names.
+func Rename(items []ListItem, names map[ListItem]string) *rclone.Job {
+	return BatchOperation(
+		"Rename", "Renaming", "", "",
+		[]string{"/sync/move", "/operations/movefile"}, items, BatchOptions{}, names,
+	)
+}
+
+// BatchOperation starts a batch job on a list of items, and returns the
+// job which tracks the whole batch. The options are passed to rclone with each
+// command, and the filter rules, if any, are applied to the directories within
+// the items. If dstNames is provided, the items are
+// transferred to the destination with their corresponding names from dstNames.
 //
 //gocyclo:ignore
 func BatchOperation(
 	name, desc, dstFs, dstRemote string, endpoints []string, items []ListItem,
-) {
+	opts BatchOptions, dstNames ...map[ListItem]string,
+) *rclone.Job {
 	if items == nil {
-		return
+		return nil
 	}
 
 	id := rclone.GetNewJobID(name)
 
+	jobDesc := desc
+	if label := opts.Preset.Label(); label != "" {
+		jobDesc += " (" + label + ")"
+	}
+
 	job := rclone.NewJob(
-		name, desc, id,
+		name, jobDesc, id,
 		name+"/"+strconv.FormatInt(id, 10),
 	)
 
@@ -121,6 +252,19 @@ func BatchOperation(
 			var endpoint string
 			var description string
 
+			itemDstFs, itemDstRemote, itemDstName := dstFs, dstRemote, item.Name
+			if dstNames != nil {
+				if dstName, ok := dstNames[0][item]; ok {
+					itemDstName = dstName
+				}
+			}
+			if conflict, ok := opts.Conflicts[item]; ok && conflict.Action == ConflictKeepBoth {
+				itemDstName = conflict.Name
+			}
+			if name == "Rename" {
+				itemDstFs, itemDstRemote = item.FS, filepath.Dir(item.Path)
+			}
+
 			select {
 			case <-mainJob.Context.Done():
 				break
@@ -134,13 +278,32 @@ func BatchOperation(
 				endpoint = endpoints[1]
 			}
 
-			command := batchCommand(name, dstFs, dstRemote, item)
+			command := batchCommand(name, itemDstFs, filepath.Join(itemDstRemote, itemDstName), item, opts)
+			if command == nil {
+				continue
+			}
+
 			command["_group"] = mainJob.Group
 
 			description += "(" + strconv.Itoa(i+1) + "/" + strconv.Itoa(len(items)) + ") "
 			description += desc + " " + filepath.Base(item.Path)
-			if desc != "Deleting" {
+			switch desc {
+			case "Deleting":
+
+			case "Changing tier of":
+				description += " -> " + opts.Tier
+
+			case "Renaming":
+				description += " -> " + itemDstName
+
+			default:
 				description += " -> " + dstFs + dstRemote
+				if itemDstName != item.Name {
+					description += " as " + itemDstName
+				}
+			}
+			if label := opts.Preset.Label(); label != "" {
+				description += " (" + label + ")"
 			}
 
 			job, err := rclone.SendCommandAsync(
@@ -160,19 +323,32 @@ func BatchOperation(
 
 			jobInfo, err := rclone.GetJobReply(job)
 			if err != nil || jobInfo.Error != "" {
+				jobErr = jobInfo.Error
 				break
 			}
 
 			refreshItems := []ListItem{}
+			if opts.Preset.DryRun {
+				goto StopJob
+			}
 
-			if name == "Delete" || name == "Move" {
+			if (name == "Delete" || name == "Move" || name == "Rename") &&
+				(!item.IsDir || opts.Filter.IsEmpty()) {
 				item.RefreshAddItem = false
 				refreshItems = append(refreshItems, item)
 			}
 
-			if name == "Copy" || name == "Move" {
-				item.FS = dstFs
-				item.Path = filepath.Join(dstRemote, item.Name)
+			if name == "SetTier" && !item.IsDir {
+				item.Tier = opts.Tier
+				item.RefreshAddItem = true
+				refreshItems = append(refreshItems, item)
+			}
+
+			if name == "Copy" || name == "Move" || name == "Sync" || name == "Rename" {
+				item.Tier = ""
+				item.FS = itemDstFs
+				item.Name = itemDstName
+				item.Path = filepath.Join(itemDstRemote, itemDstName)
 				item.RefreshAddItem = true
 
 				if item.Size == -1 {
@@ -190,6 +366,7 @@ func BatchOperation(
 				refreshItems = append(refreshItems, item)
 			}
 
+		StopJob:
 			job.RefreshItems = refreshItems
 
 			rclone.StopJob(job, jobInfo.Error)
@@ -197,6 +374,68 @@ func BatchOperation(
 
 		rclone.StopJob(mainJob, jobErr, struct{}{})
 	}(job)
+
+	return job
+}
+
+// startJob starts a job for a single command, which is tracked in the same way as
+// a batch job. If finished is provided, it is called with the job's output once the
+// command has completed, and returns the items which have to be refreshed.
+func startJob(
+	name, desc, endpoint string, command map[string]interface{},
+	finished func(jobInfo rclone.JobInfo) []ListItem,
+) *rclone.Job {
+	id := rclone.GetNewJobID(name)
+
+	mainJob := rclone.NewJob(
+		name, desc, id,
+		name+"/"+strconv.FormatInt(id, 10),
+	)
+
+	rclone.AddJobToQueue(mainJob, struct{}{})
+
+	go func() {
+		var jobErr string
+
+		command["_group"] = mainJob.Group
+
+		job, err := rclone.SendCommandAsync(
+			"_"+name, desc,
+			command, endpoint, struct{}{},
+		)
+		if err != nil {
+			rclone.StopJob(mainJob, err.Error(), struct{}{})
+			return
+		}
+
+		job.Group = mainJob.Group
+		job.Context = mainJob.Context
+		job.Cancel = mainJob.Cancel
+
+		go rclone.MonitorJob(job, struct{}{})
+
+		jobInfo, err := rclone.GetJobReply(job)
+		if err != nil {
+			jobErr = err.Error()
+		}
+		if finished != nil {
+			job.RefreshItems = finished(jobInfo)
+		}
+
+		rclone.StopJob(job, jobErr)
+		rclone.StopJob(mainJob, jobErr, struct{}{})
+	}()
+
+	return mainJob
+}
+
+// batchOptions returns the optional options for a batch operation.
+func batchOptions(opts []BatchOptions) BatchOptions {
+	if opts == nil {
+		return BatchOptions{}
+	}
+
+	return opts[0]
 }
 
 // stat returns the information for the item.
@@ -221,31 +460,84 @@ func stat(ctx context.Context, fs, remote string) (ListItem, error) {
 	return listItem, err
 }
 
-// batchCommand returns a command in an rclone-parseable format.
-func batchCommand(operation, dstFs, dstRemote string, item ListItem) map[string]interface{} {
+// batchCommand returns a command in an rclone-parseable format, along with
+// the filter rules for directories and the rclone options from the batch options.
+// If the item already exists within the destination, the conflict's action is
+// applied to the command, and nil is returned if the item should be skipped.
+//
+//gocyclo:ignore
+func batchCommand(operation, dstFs, dstPath string, item ListItem, opts BatchOptions) map[string]interface{} {
 	var command map[string]interface{}
 
+	conflict, hasConflict := opts.Conflicts[item]
+	if hasConflict && conflict.skip() {
+		return nil
+	}
+
 	switch operation {
-	case "Copy", "Move":
+	case "Copy", "Move", "Sync", "Rename":
 		if item.IsDir {
 			command = map[string]interface{}{
-				"srcFs": item.Path,
-				"dstFs": dstFs + filepath.Join(dstRemote, item.Name),
+				"srcFs": item.FS + item.Path,
+				"dstFs": dstFs + dstPath,
+			}
+
+			if operation == "Rename" {
+				command["deleteEmptySrcDirs"] = true
 			}
 		} else {
 			command = map[string]interface{}{
 				"srcFs":     item.FS,
 				"srcRemote": item.Path,
 				"dstFs":     dstFs,
-				"dstRemote": filepath.Join(dstRemote, item.Name),
+				"dstRemote": dstPath,
+			}
+		}
+
+	case "SetTier":
+		if item.IsDir {
+			command = map[string]interface{}{
+				"fs": item.FS + item.Path,
+			}
+		} else {
+			command = map[string]interface{}{
+				"fs":     item.FS,
+				"remote": item.Path,
 			}
 		}
 
+		command["tier"] = opts.Tier
+
 	case "Delete":
 		command = map[string]interface{}{
 			"fs":     item.FS,
 			"remote": item.Path,
 		}
+
+		if item.IsDir && !opts.Filter.IsEmpty() {
+			command = map[string]interface{}{
+				"fs": item.FS + item.Path,
+			}
+		}
+	}
+
+	if item.IsDir && !opts.Filter.IsEmpty() {
+		command["_filter"] = opts.Filter.Params()
+	}
+
+	config := opts.Preset.Params()
+	if hasConflict && item.IsDir {
+		switch conflict.Action {
+		case ConflictSkip:
+			config["IgnoreExisting"] = true
+
+		case ConflictKeepNewer:
+			config["UpdateOlder"] = true
+		}
+	}
+
+	if len(config) > 0 {
+		command["_config"] = config
 	}
 
 	return command
@@ -0,0 +1,127 @@
+package rclone
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Filter stores a named set of filter rules, which are passed to rclone
+// as the "_filter" parameter of an operation.
+type Filter struct {
+	Name string `json:"name"`
+
+	IncludeRule []string `json:"includeRule,omitempty"`
+	ExcludeRule []string `json:"excludeRule,omitempty"`
+
+	MinSize string `json:"minSize,omitempty"`
+	MaxSize string `json:"maxSize,omitempty"`
+	MinAge  string `json:"minAge,omitempty"`
+	MaxAge  string `json:"maxAge,omitempty"`
+
+	IgnoreCase bool `json:"ignoreCase,omitempty"`
+}
+
+var filters = newJSONStore[Filter]("filters")
+
+// LoadFilters loads the filter sets from the provided file.
+func LoadFilters(file string) error {
+	return filters.load(file)
+}
+
+// GetFilters returns the list of filter sets, sorted by their names.
+func GetFilters() []Filter {
+	list := filters.list()
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+// GetFilter returns the filter set with the provided name.
+func GetFilter(name string) (Filter, bool) {
+	return filters.find(func(f Filter) bool {
+		return f.Name == name
+	})
+}
+
+// SaveFilter adds the filter set, or replaces the filter set with the same name.
+func SaveFilter(filter Filter) error {
+	filter.Name = strings.TrimSpace(filter.Name)
+	if filter.Name == "" {
+		return fmt.Errorf("No filter name specified")
+	}
+
+	if filter.IsEmpty() {
+		return fmt.Errorf("%s: No filter rules specified", filter.Name)
+	}
+
+	return filters.put(filter, func(f Filter) bool {
+		return f.Name == filter.Name
+	})
+}
+
+// RemoveFilter removes the filter set with the provided name.
+func RemoveFilter(name string) error {
+	found, err := filters.remove(func(f Filter) bool {
+		return f.Name == name
+	})
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("%s: No such filter", name)
+	}
+
+	return nil
+}
+
+// ParseFilterRules parses a semicolon-separated list of filter rules.
+func ParseFilterRules(rules string) []string {
+	var parsed []string
+
+	for _, rule := range strings.Split(rules, ";") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			parsed = append(parsed, rule)
+		}
+	}
+
+	return parsed
+}
+
+// IsEmpty returns whether the filter set has no rules.
+func (f Filter) IsEmpty() bool {
+	return f.IncludeRule == nil && f.ExcludeRule == nil &&
+		f.MinSize == "" && f.MaxSize == "" &&
+		f.MinAge == "" && f.MaxAge == ""
+}
+
+// Params returns the filter set as rclone's filter parameters.
+func (f Filter) Params() map[string]interface{} {
+	params := make(map[string]interface{})
+
+	if f.IncludeRule != nil {
+		params["IncludeRule"] = f.IncludeRule
+	}
+	if f.ExcludeRule != nil {
+		params["ExcludeRule"] = f.ExcludeRule
+	}
+	if f.IgnoreCase {
+		params["IgnoreCase"] = true
+	}
+
+	for key, value := range map[string]string{
+		"MinSize": f.MinSize,
+		"MaxSize": f.MaxSize,
+		"MinAge":  f.MinAge,
+		"MaxAge":  f.MaxAge,
+	} {
+		if value != "" {
+			params[key] = value
+		}
+	}
+
+	return params
+}
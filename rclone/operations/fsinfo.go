@@ -2,6 +2,7 @@ package rclone
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/darkhz/rclone-tui/rclone"
 	"github.com/mitchellh/mapstructure"
@@ -55,3 +56,25 @@ func FsInfo(id, fs string) (FsDetail, error) {
 
 	return detail, nil
 }
+
+// RemoteType returns the backend type of the remote, for example "s3".
+// An empty string is returned if the type cannot be determined.
+func RemoteType(fs string) string {
+	command := map[string]interface{}{
+		"name": strings.TrimSuffix(fs, ":"),
+	}
+
+	response, err := rclone.SendCommand(command, "/config/get")
+	if err != nil {
+		return ""
+	}
+
+	config := make(map[string]interface{})
+	if err := response.Decode(&config); err != nil {
+		return ""
+	}
+
+	remoteType, _ := config["type"].(string)
+
+	return remoteType
+}
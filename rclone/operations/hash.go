@@ -0,0 +1,186 @@
+package rclone
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/bytefmt"
+	"github.com/darkhz/rclone-tui/rclone"
+)
+
+// maxCatSize is the maximum size of a file which can be read by Cat.
+const maxCatSize = 16 * 1024 * 1024
+
+// FileHash stores the hash of a file.
+type FileHash struct {
+	Name string
+	Hash string
+}
+
+// HashResult stores the result of verifying a file's hash.
+type HashResult struct {
+	FileHash
+
+	Expected string
+	Result   string
+}
+
+// Hashsum computes the hashes of the provided type for the items. If download is set,
+// the hashes are computed by downloading the files. The names of the files are relative
+// to the directory the items are in, and directories are hashed recursively.
+func Hashsum(id string, items []ListItem, hashType string, download bool) ([]FileHash, error) {
+	var hashes []FileHash
+
+	for _, item := range items {
+		command := map[string]interface{}{
+			"fs":       item.FS + item.Path,
+			"hashType": hashType,
+			"download": download,
+		}
+
+		job, err := rclone.SendCommandAsync("UI:Explorer:"+id, "Computing hashes for "+item.Name, command, "/operations/hashsum")
+		if err != nil {
+			return nil, err
+		}
+
+		jobInfo, err := rclone.GetJobReply(job)
+		if err != nil {
+			return nil, err
+		}
+
+		hashsum, _ := jobInfo.Output["hashsum"].([]interface{})
+
+		for _, h := range hashsum {
+			line, ok := h.(string)
+			if !ok {
+				continue
+			}
+
+			hash, name, ok := parseChecksum(line)
+			if !ok {
+				continue
+			}
+
+			if item.IsDir {
+				name = item.Name + "/" + name
+			} else {
+				name = item.Name
+			}
+
+			hashes = append(hashes, FileHash{Name: name, Hash: hash})
+		}
+	}
+
+	return hashes, nil
+}
+
+// ParseChecksums parses checksums in the format written by rclone hashsum or md5sum,
+// and in the BSD format, and returns a map of file names to their hashes.
+func ParseChecksums(text string) map[string]string {
+	checksums := make(map[string]string)
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		hash, name, ok := parseChecksum(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		checksums[name] = hash
+	}
+
+	return checksums
+}
+
+// VerifyHashes compares the hashes with the provided checksums.
+func VerifyHashes(hashes []FileHash, checksums map[string]string) []HashResult {
+	results := make([]HashResult, 0, len(hashes))
+
+	for _, fileHash := range hashes {
+		result := HashResult{FileHash: fileHash}
+
+		expected, ok := checksums[fileHash.Name]
+		if !ok {
+			expected, ok = checksums[filepath.Base(fileHash.Name)]
+		}
+
+		switch {
+		case !ok:
+			result.Result = "MISSING"
+
+		case strings.EqualFold(expected, fileHash.Hash):
+			result.Result = "OK"
+
+		default:
+			result.Result = "FAILED"
+		}
+
+		result.Expected = expected
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// WriteChecksums writes the hashes to a checksum file with the provided name
+// within the remote and path.
+func WriteChecksums(fs, remote, name string, hashes []FileHash) error {
+	var sums strings.Builder
+
+	for _, fileHash := range hashes {
+		sums.WriteString(fileHash.Hash + "  " + fileHash.Name + "\n")
+	}
+
+	return rclone.UploadFile(fs, remote, name, strings.NewReader(sums.String()))
+}
+
+// Cat returns the contents of the file at the provided path.
+func Cat(fspath string) (string, error) {
+	fs, remote := SplitFS(fspath)
+
+	data, total, err := rclone.ReadObject(fs, remote, 0, maxCatSize)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("Cannot read %s: %s", fspath, err.Error())
+	}
+
+	if total > maxCatSize {
+		return "", fmt.Errorf(
+			"%s is too large to read (%s, maximum is %s)", fspath,
+			bytefmt.ByteSize(uint64(total)), bytefmt.ByteSize(maxCatSize),
+		)
+	}
+
+	return string(data), nil
+}
+
+// parseChecksum parses a single line of a checksum file.
+func parseChecksum(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+
+	if open := strings.Index(line, " ("); open > 0 {
+		if end := strings.LastIndex(line, ") = "); end > open {
+			return line[end+4:], cleanChecksumName(line[open+2 : end]), true
+		}
+	}
+
+	fields := strings.SplitN(line, " ", 2)
+	if len(fields) != 2 {
+		return "", "", false
+	}
+
+	name := strings.TrimLeft(fields[1], " ")
+	name = strings.TrimPrefix(name, "*")
+
+	return fields[0], cleanChecksumName(name), true
+}
+
+// cleanChecksumName normalizes a file name within a checksum file.
+func cleanChecksumName(name string) string {
+	return strings.TrimPrefix(filepath.ToSlash(name), "./")
+}
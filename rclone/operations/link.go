@@ -0,0 +1,67 @@
+package rclone
+
+import (
+	"sort"
+	"time"
+)
+
+// LinkOptions stores the options for generating a public link. Expire is
+// the duration after which the link expires, for example "1d".
+type LinkOptions struct {
+	Expire string
+	Unlink bool
+}
+
+// Link stores a public link which was generated for an item.
+type Link struct {
+	FS   string `json:"fs"`
+	Path string `json:"path"`
+	URL  string `json:"url"`
+
+	Expire  string `json:"expire,omitempty"`
+	Created int64  `json:"created"`
+}
+
+var links = newJSONStore[Link]("links")
+
+// LoadLinks loads the generated public links from the provided file.
+func LoadLinks(file string) error {
+	return links.load(file)
+}
+
+// GetLinks returns the list of generated public links, with the most recent link first.
+func GetLinks() []Link {
+	list := links.list()
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Created > list[j].Created
+	})
+
+	return list
+}
+
+// Description returns the creation time and the expiry of the link.
+func (l Link) Description() string {
+	desc := "created " + time.Unix(l.Created, 0).Format("Mon 01/02 15:04")
+	if l.Expire != "" {
+		desc += ", expires after " + l.Expire
+	}
+
+	return desc
+}
+
+// saveLink records the generated public link, and replaces the previous
+// link for the same item.
+func saveLink(link Link) error {
+	return links.put(link, func(l Link) bool {
+		return l.FS == link.FS && l.Path == link.Path
+	})
+}
+
+// removeLink removes the recorded public link for the item.
+func removeLink(fs, path string) error {
+	_, err := links.remove(func(l Link) bool {
+		return l.FS == fs && l.Path == path
+	})
+
+	return err
+}
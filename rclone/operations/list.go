@@ -2,6 +2,7 @@ package rclone
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
 	"strings"
 	"time"
@@ -27,6 +28,7 @@ type ListItem struct {
 	Name     string `mapstructure:"Name"`
 	Path     string `mapstructure:"Path"`
 	Size     int64  `mapstructure:"Size"`
+	Tier     string `mapstructure:"Tier"`
 
 	FS               string
 	ISize            string
@@ -42,6 +44,11 @@ func ListFS(id, fstype, path string) (List, error) {
 	var list List
 	var fs, desc string
 
+	if IsLocal(fstype) {
+		list, err := ListLocal(path)
+		return applyDirSizes(list), err
+	}
+
 	if strings.Contains(fstype, ":") {
 		fs = fstype
 		if path != "" {
@@ -77,7 +84,7 @@ func ListFS(id, fstype, path string) (List, error) {
 		list.Items[j] = appendItemDetails(item, fs)
 	}
 
-	return list, err
+	return applyDirSizes(list), err
 }
 
 func appendItemDetails(item ListItem, fs string) ListItem {
@@ -99,6 +106,74 @@ func appendItemDetails(item ListItem, fs string) ListItem {
 	return item
 }
 
+// StatFS returns the directory entry for the provided remote and path.
+func StatFS(ctx context.Context, fs, path string) (ListItem, error) {
+	item, err := stat(ctx, fs, path)
+	if err != nil {
+		return ListItem{}, err
+	}
+
+	if item.Name == "" {
+		if path != "" {
+			return ListItem{}, fmt.Errorf("%s%s: No such file or directory", fs, path)
+		}
+
+		item.IsDir = true
+		item.Size = -1
+	}
+
+	return appendItemDetails(item, fs), nil
+}
+
+// Exists checks whether the provided path exists within the remote.
+func Exists(ctx context.Context, fs, path string) (bool, error) {
+	item, err := stat(ctx, fs, path)
+	if err != nil {
+		return false, err
+	}
+
+	return item.Name != "", nil
+}
+
+// listDir returns the directory entries from the provided remote and path,
+// without tracking the listing as a job.
+func listDir(ctx context.Context, fs, remote string) ([]ListItem, error) {
+	var items []ListItem
+
+	command := map[string]interface{}{
+		"fs":     fs,
+		"remote": remote,
+	}
+
+	response, err := rclone.SendCommand(command, "/operations/list", ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := struct {
+		List []ListItem `json:"list"`
+	}{}
+
+	if err := response.Decode(&reply); err != nil {
+		return nil, err
+	}
+
+	for _, item := range reply.List {
+		items = append(items, appendItemDetails(item, fs))
+	}
+
+	return items, nil
+}
+
+// SplitFS splits a "remote:path" or a local path into the remote and the path.
+func SplitFS(fspath string) (string, string) {
+	if i := strings.Index(fspath, ":"); i > 0 && !filepath.IsAbs(fspath) {
+		return fspath[:i+1], strings.Trim(fspath[i+1:], "/")
+	}
+
+	return "/", strings.Trim(filepath.ToSlash(fspath), "/")
+}
+
 // ListRemotes lists the configured remotes.
 func ListRemotes(ctx context.Context) ([]string, error) {
 	return GetDataSlice(ctx, "/config/listremotes", "remotes")
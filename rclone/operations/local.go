@@ -0,0 +1,461 @@
+package rclone
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"code.cloudfoundry.org/bytefmt"
+	"github.com/darkhz/rclone-tui/rclone"
+	"github.com/mitchellh/mapstructure"
+)
+
+// LocalFS is the remote name for the filesystem of the machine which rclone-tui
+// is running on. Items within this filesystem are transferred by rclone-tui itself,
+// by uploading them to or downloading them from the rclone host.
+const LocalFS = "[this machine]:"
+
+// localTransfer stores the progress of a local file transfer.
+// The size and bytes fields are accessed atomically, and are placed first
+// to keep them 64-bit aligned on 32-bit platforms.
+type localTransfer struct {
+	size, bytes int64
+
+	job  *rclone.Job
+	desc string
+
+	name      string
+	startTime time.Time
+
+	lock sync.Mutex
+}
+
+// IsLocal returns whether the remote is the local filesystem.
+func IsLocal(fs string) bool {
+	return fs == LocalFS
+}
+
+// ListLocal returns a list of directory entries from the provided local path.
+func ListLocal(path string) (List, error) {
+	list := List{Path: path}
+
+	entries, err := os.ReadDir(localPath(path))
+	if err != nil {
+		return List{}, err
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		item := ListItem{
+			Name:    entry.Name(),
+			Path:    filepath.ToSlash(filepath.Join(path, entry.Name())),
+			IsDir:   info.IsDir(),
+			Size:    info.Size(),
+			ModTime: info.ModTime().Format(time.RFC3339),
+		}
+		if item.IsDir {
+			item.Size = -1
+		}
+
+		list.Items = append(list.Items, appendItemDetails(item, LocalFS))
+	}
+
+	return list, nil
+}
+
+// LocalCopy copies items from the local filesystem to the destination remote and path
+// by uploading them, or copies items from a remote to the local filesystem by downloading
+// them. The transfers are tracked in the same way as a batch job.
+func LocalCopy(items []ListItem, dstFs, dstRemote string) *rclone.Job {
+	var name, desc string
+	var err error
+
+	if IsLocal(dstFs) {
+		name, desc = "Download", "Downloading"
+	} else {
+		name, desc = "Upload", "Uploading"
+	}
+
+	for _, item := range items {
+		if IsLocal(item.FS) == IsLocal(dstFs) {
+			err = fmt.Errorf("Cannot copy local and remote items together")
+			break
+		}
+	}
+
+	id := rclone.GetNewJobID(name)
+
+	mainJob := rclone.NewJob(
+		name, desc, id,
+		name+"/"+strconv.FormatInt(id, 10),
+	)
+
+	rclone.AddJobToQueue(mainJob, struct{}{})
+
+	go func() {
+		var jobErr string
+
+		if err != nil {
+			rclone.StopJob(mainJob, err.Error(), struct{}{})
+			return
+		}
+
+		for i, item := range items {
+			var refreshItem ListItem
+
+			t := &localTransfer{
+				job: mainJob,
+				desc: "(" + strconv.Itoa(i+1) + "/" + strconv.Itoa(len(items)) + ") " +
+					desc + " " + item.Name + " -> " + dstFs + dstRemote,
+			}
+
+			stop := make(chan struct{})
+			go t.monitor(stop)
+
+			if name == "Upload" {
+				refreshItem, err = t.upload(item, dstFs, dstRemote)
+			} else {
+				refreshItem, err = t.download(item, dstRemote)
+			}
+
+			close(stop)
+
+			if err != nil {
+				jobErr = err.Error()
+				if mainJob.Context.Err() != nil {
+					jobErr = mainJob.Description + " cancelled"
+				}
+
+				break
+			}
+
+			refreshItem.RefreshAddItem = true
+
+			rclone.PublishJobInfo(rclone.JobInfo{
+				Type:        "_" + name,
+				Group:       mainJob.Group,
+				Description: t.desc,
+				Finished:    true,
+
+				RefreshItems: []ListItem{refreshItem},
+			})
+		}
+
+		rclone.StopJob(mainJob, jobErr, struct{}{})
+	}()
+
+	return mainJob
+}
+
+// upload uploads the local item to the destination remote and path.
+func (t *localTransfer) upload(item ListItem, dstFs, dstRemote string) (ListItem, error) {
+	root := localPath(item.Path)
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		remote := filepath.ToSlash(filepath.Join(dstRemote, item.Name, rel))
+		if !item.IsDir {
+			remote = filepath.ToSlash(filepath.Join(dstRemote, item.Name))
+		}
+
+		if entry.IsDir() {
+			var reply map[string]interface{}
+
+			response, err := rclone.SendCommand(map[string]interface{}{
+				"fs":     dstFs,
+				"remote": remote,
+			}, "/operations/mkdir", t.job.Context)
+			if err != nil {
+				return err
+			}
+
+			if err := response.Decode(&reply); err != nil {
+				return err
+			}
+
+			if replyErr, ok := reply["error"].(string); ok {
+				return fmt.Errorf(replyErr)
+			}
+
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+
+		t.start(entry.Name(), info.Size())
+
+		return rclone.UploadFile(
+			dstFs, filepath.ToSlash(filepath.Dir(remote)), entry.Name(),
+			t.reader(file), t.job.Context,
+		)
+	})
+	if err != nil {
+		return ListItem{}, err
+	}
+
+	listItem, err := StatFS(t.job.Context, dstFs, filepath.ToSlash(filepath.Join(dstRemote, item.Name)))
+	if err != nil {
+		return ListItem{}, err
+	}
+
+	return listItem, nil
+}
+
+// download downloads the remote item to the local destination path.
+func (t *localTransfer) download(item ListItem, dstRemote string) (ListItem, error) {
+	var files []ListItem
+
+	dstPath := filepath.Join(localPath(dstRemote), item.Name)
+
+	if item.IsDir {
+		list, err := t.listRecursive(item)
+		if err != nil {
+			return ListItem{}, err
+		}
+
+		if err := os.MkdirAll(dstPath, 0755); err != nil {
+			return ListItem{}, err
+		}
+
+		for _, entry := range list {
+			rel := strings.TrimPrefix(entry.Path, item.Path+"/")
+			if entry.IsDir {
+				if err := os.MkdirAll(filepath.Join(dstPath, filepath.FromSlash(rel)), 0755); err != nil {
+					return ListItem{}, err
+				}
+
+				continue
+			}
+
+			entry.FS = item.FS
+			entry.Name = rel
+			files = append(files, entry)
+		}
+	} else {
+		item.Name = ""
+		files = append(files, item)
+	}
+
+	for _, file := range files {
+		if err := t.downloadFile(file, filepath.Join(dstPath, filepath.FromSlash(file.Name))); err != nil {
+			return ListItem{}, err
+		}
+	}
+
+	info, err := os.Stat(dstPath)
+	if err != nil {
+		return ListItem{}, err
+	}
+
+	listItem := ListItem{
+		Name:    info.Name(),
+		Path:    filepath.ToSlash(filepath.Join(dstRemote, info.Name())),
+		IsDir:   info.IsDir(),
+		Size:    info.Size(),
+		ModTime: info.ModTime().Format(time.RFC3339),
+	}
+	if listItem.IsDir {
+		listItem.Size = -1
+	}
+
+	return appendItemDetails(listItem, LocalFS), nil
+}
+
+// downloadFile downloads the remote file to the local path.
+func (t *localTransfer) downloadFile(item ListItem, path string) error {
+	object, size, err := rclone.OpenObject(t.job.Context, item.FS, item.Path)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	if size < 0 {
+		size = item.Size
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	t.start(filepath.Base(item.Path), size)
+
+	_, err = io.Copy(file, t.reader(object))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+
+	return err
+}
+
+// listRecursive lists all the entries within the remote directory.
+func (t *localTransfer) listRecursive(item ListItem) ([]ListItem, error) {
+	var list List
+
+	command := map[string]interface{}{
+		"fs":     item.FS,
+		"remote": item.Path,
+		"opt": map[string]interface{}{
+			"recurse":    true,
+			"noModTime":  true,
+			"noMimeType": true,
+		},
+	}
+
+	job, err := rclone.SendCommandAsync(
+		"_"+t.job.Type, "Listing "+item.FS+item.Path,
+		command, "/operations/list", struct{}{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Group = t.job.Group
+	job.Context = t.job.Context
+	job.Cancel = t.job.Cancel
+
+	go rclone.MonitorJob(job, struct{}{})
+
+	jobInfo, err := rclone.GetJobReply(job)
+	rclone.StopJob(job, jobInfo.Error)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mapstructure.Decode(jobInfo.Output, &list); err != nil {
+		return nil, err
+	}
+
+	return list.Items, nil
+}
+
+// start resets the transfer's progress for a new file.
+func (t *localTransfer) start(name string, size int64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	atomic.StoreInt64(&t.bytes, 0)
+	atomic.StoreInt64(&t.size, size)
+
+	t.name = name
+	t.startTime = time.Now()
+}
+
+// reader returns a reader which tracks the transfer's progress, and stops
+// reading once the transfer has been cancelled.
+func (t *localTransfer) reader(r io.Reader) io.Reader {
+	return &progressReader{t: t, r: r}
+}
+
+// monitor publishes the transfer's progress until stop is closed.
+func (t *localTransfer) monitor(stop chan struct{}) {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+
+		case <-ticker.C:
+		}
+
+		t.lock.Lock()
+		name, startTime := t.name, t.startTime
+		t.lock.Unlock()
+
+		bytes, size := atomic.LoadInt64(&t.bytes), atomic.LoadInt64(&t.size)
+
+		stat := rclone.TransferStat{
+			Name:  name,
+			Size:  size,
+			Bytes: bytes,
+			Group: t.job.Group,
+		}
+
+		if elapsed := time.Since(startTime).Seconds(); elapsed > 0 {
+			stat.Speed = float64(bytes) / elapsed
+		}
+		if size > 0 {
+			stat.Percentage = bytes * 100 / size
+
+			if stat.Speed > 0 {
+				stat.Eta = int64(float64(size-bytes) / stat.Speed)
+			}
+		}
+
+		desc := t.desc
+		if name != "" && size > 0 {
+			desc += " (" + name + ", " + bytefmt.ByteSize(uint64(size)) + ")"
+		}
+
+		rclone.PublishJobInfo(rclone.JobInfo{
+			ID:          t.job.ID,
+			Type:        t.job.Type,
+			Group:       t.job.Group,
+			Description: desc,
+
+			CurrentTransfer: stat,
+		})
+	}
+}
+
+// progressReader is a reader which tracks a local transfer's progress.
+type progressReader struct {
+	t *localTransfer
+	r io.Reader
+}
+
+// Read reads data from the underlying reader, and updates the transfer's progress.
+func (p *progressReader) Read(b []byte) (int, error) {
+	if err := p.t.job.Context.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := p.r.Read(b)
+	atomic.AddInt64(&p.t.bytes, int64(n))
+
+	return n, err
+}
+
+// localPath returns the path within the local filesystem.
+func localPath(path string) string {
+	root := "/"
+	if runtime.GOOS == "windows" {
+		root = os.Getenv("SystemDrive") + `\`
+	}
+
+	return filepath.Join(root, filepath.FromSlash(path))
+}
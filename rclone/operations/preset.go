@@ -0,0 +1,124 @@
+package rclone
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Preset stores a named set of rclone options, which are passed to rclone
+// as the "_config" parameter of an operation.
+type Preset struct {
+	Name string `json:"name"`
+
+	Transfers   int    `json:"transfers,omitempty"`
+	Checkers    int    `json:"checkers,omitempty"`
+	BwLimitFile string `json:"bwLimitFile,omitempty"`
+
+	CheckSum       bool `json:"checksum,omitempty"`
+	IgnoreExisting bool `json:"ignoreExisting,omitempty"`
+	UpdateOlder    bool `json:"update,omitempty"`
+	NoTraverse     bool `json:"noTraverse,omitempty"`
+	DryRun         bool `json:"dryRun,omitempty"`
+}
+
+var presets = newJSONStore[Preset]("presets")
+
+// LoadPresets loads the option presets from the provided file.
+func LoadPresets(file string) error {
+	return presets.load(file)
+}
+
+// GetPresets returns the list of option presets, sorted by their names.
+func GetPresets() []Preset {
+	list := presets.list()
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+// GetPreset returns the option preset with the provided name.
+func GetPreset(name string) (Preset, bool) {
+	return presets.find(func(p Preset) bool {
+		return p.Name == name
+	})
+}
+
+// SavePreset adds the option preset, or replaces the preset with the same name.
+func SavePreset(preset Preset) error {
+	preset.Name = strings.TrimSpace(preset.Name)
+	if preset.Name == "" {
+		return fmt.Errorf("No preset name specified")
+	}
+
+	return presets.put(preset, func(p Preset) bool {
+		return p.Name == preset.Name
+	})
+}
+
+// RemovePreset removes the option preset with the provided name.
+func RemovePreset(name string) error {
+	found, err := presets.remove(func(p Preset) bool {
+		return p.Name == name
+	})
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("%s: No such preset", name)
+	}
+
+	return nil
+}
+
+// IsEmpty returns whether the preset does not change any options.
+func (p Preset) IsEmpty() bool {
+	return p.Transfers == 0 && p.Checkers == 0 && p.BwLimitFile == "" &&
+		!p.CheckSum && !p.IgnoreExisting && !p.UpdateOlder &&
+		!p.NoTraverse && !p.DryRun
+}
+
+// Label returns the label which identifies the preset in job descriptions.
+func (p Preset) Label() string {
+	if p.IsEmpty() {
+		return ""
+	}
+
+	if p.Name == "" {
+		return "custom options"
+	}
+
+	return "preset: " + p.Name
+}
+
+// Params returns the preset as rclone's config parameters.
+func (p Preset) Params() map[string]interface{} {
+	params := make(map[string]interface{})
+
+	if p.Transfers > 0 {
+		params["Transfers"] = p.Transfers
+	}
+	if p.Checkers > 0 {
+		params["Checkers"] = p.Checkers
+	}
+	if p.BwLimitFile != "" {
+		params["BwLimitFile"] = p.BwLimitFile
+	}
+
+	for key, value := range map[string]bool{
+		"CheckSum":       p.CheckSum,
+		"IgnoreExisting": p.IgnoreExisting,
+		"UpdateOlder":    p.UpdateOlder,
+		"NoTraverse":     p.NoTraverse,
+		"DryRun":         p.DryRun,
+	} {
+		if value {
+			params[key] = true
+		}
+	}
+
+	return params
+}
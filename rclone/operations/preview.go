@@ -0,0 +1,108 @@
+package rclone
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/darkhz/rclone-tui/rclone"
+)
+
+// PreviewPageSize is the maximum amount of data fetched for a single preview page.
+const PreviewPageSize = 64 * 1024
+
+// Preview stores a single page of a file's contents.
+type Preview struct {
+	Data   []byte
+	Binary bool
+
+	Offset, Next, Total int64
+}
+
+// ReadPreview reads a page of the file's contents from the offset. A page of
+// text is cut at its last line, so that the next page starts at a new line.
+func ReadPreview(item ListItem, offset int64) (Preview, error) {
+	data, total, err := rclone.ReadObject(item.FS, item.Path, offset, PreviewPageSize)
+	if err != nil && err != io.EOF {
+		return Preview{}, err
+	}
+
+	preview := Preview{
+		Data:   data,
+		Binary: isBinary(data),
+		Offset: offset,
+		Next:   offset + int64(len(data)),
+		Total:  total,
+	}
+
+	if !preview.Binary && len(data) == PreviewPageSize && preview.Next < total {
+		if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+			preview.Data = data[:i+1]
+			preview.Next = offset + int64(i+1)
+		}
+	}
+
+	return preview, nil
+}
+
+// Lines returns the lines within the page.
+func (p Preview) Lines() []string {
+	return strings.Split(strings.TrimSuffix(string(p.Data), "\n"), "\n")
+}
+
+// HexDump returns a hex dump of the page, with the offsets relative to the file.
+func (p Preview) HexDump() []string {
+	var dump []string
+
+	for i := 0; i < len(p.Data); i += 16 {
+		var hex, ascii strings.Builder
+
+		end := i + 16
+		if end > len(p.Data) {
+			end = len(p.Data)
+		}
+
+		for j := i; j < i+16; j++ {
+			if j == i+8 {
+				hex.WriteByte(' ')
+			}
+
+			if j >= end {
+				hex.WriteString("   ")
+				continue
+			}
+
+			b := p.Data[j]
+
+			fmt.Fprintf(&hex, "%02x ", b)
+			if b >= 0x20 && b < 0x7f {
+				ascii.WriteByte(b)
+			} else {
+				ascii.WriteByte('.')
+			}
+		}
+
+		dump = append(dump, fmt.Sprintf("%08x  %s |%s|", p.Offset+int64(i), hex.String(), ascii.String()))
+	}
+
+	return dump
+}
+
+// isBinary returns whether the data is not valid text.
+func isBinary(data []byte) bool {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return true
+	}
+
+	for i := 0; i < utf8.UTFMax && len(data) > 0; i++ {
+		if utf8.Valid(data) {
+			return false
+		}
+
+		data = data[:len(data)-1]
+	}
+
+	return len(data) > 0
+}
@@ -0,0 +1,128 @@
+package rclone
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/darkhz/rclone-tui/rclone"
+)
+
+// EmptyDirs stores the empty directories within a directory. If LeaveRoot
+// is set, the directory itself is not removed, even if it is empty.
+type EmptyDirs struct {
+	Dir       ListItem
+	LeaveRoot bool
+	Empty     []ListItem
+}
+
+// FindEmptyDirs recursively lists the directory, and returns the directories
+// within it which do not contain any files, which are the directories that
+// /operations/rmdirs would remove.
+func FindEmptyDirs(dir ListItem, leaveRoot bool) (EmptyDirs, error) {
+	emptyDirs := EmptyDirs{Dir: dir, LeaveRoot: leaveRoot}
+
+	items, err := listRemoteRecursive(
+		"Rmdirs", "Finding empty directories in "+dir.FS+dir.Path,
+		dir.FS, dir.Path,
+	)
+	if err != nil {
+		return EmptyDirs{}, err
+	}
+
+	nonEmpty := make(map[string]struct{})
+	for _, item := range items {
+		if item.IsDir {
+			continue
+		}
+
+		for path := filepath.Dir(item.Path); path != "." && path != dir.Path; path = filepath.Dir(path) {
+			nonEmpty[path] = struct{}{}
+		}
+
+		nonEmpty[dir.Path] = struct{}{}
+	}
+
+	for _, item := range items {
+		if _, ok := nonEmpty[item.Path]; !ok && item.IsDir {
+			emptyDirs.Empty = append(emptyDirs.Empty, item)
+		}
+	}
+
+	if _, ok := nonEmpty[dir.Path]; !ok && !leaveRoot {
+		emptyDirs.Empty = append(emptyDirs.Empty, dir)
+	}
+
+	sort.Slice(emptyDirs.Empty, func(i, j int) bool {
+		return emptyDirs.Empty[i].Path < emptyDirs.Empty[j].Path
+	})
+
+	return emptyDirs, nil
+}
+
+// RemoveEmptyDirs removes the empty directories within each directory, and
+// refreshes the listings of the directories that were found to be empty.
+func RemoveEmptyDirs(dirs []EmptyDirs) *rclone.Job {
+	if dirs == nil {
+		return nil
+	}
+
+	id := rclone.GetNewJobID("Rmdirs")
+
+	mainJob := rclone.NewJob(
+		"Rmdirs", "Removing empty directories", id,
+		"Rmdirs/"+strconv.FormatInt(id, 10),
+	)
+
+	rclone.AddJobToQueue(mainJob, struct{}{})
+
+	go func() {
+		var jobErr string
+
+		for i, dir := range dirs {
+			command := map[string]interface{}{
+				"fs":        dir.Dir.FS,
+				"remote":    dir.Dir.Path,
+				"leaveRoot": dir.LeaveRoot,
+				"_group":    mainJob.Group,
+			}
+
+			job, err := rclone.SendCommandAsync(
+				"_Rmdirs",
+				"("+strconv.Itoa(i+1)+"/"+strconv.Itoa(len(dirs))+") "+
+					"Removing empty directories in "+dir.Dir.FS+dir.Dir.Path,
+				command, "/operations/rmdirs", struct{}{},
+			)
+			if err != nil {
+				jobErr = err.Error()
+				break
+			}
+
+			job.Group = mainJob.Group
+			job.Context = mainJob.Context
+			job.Cancel = mainJob.Cancel
+
+			go rclone.MonitorJob(job, struct{}{})
+
+			jobInfo, err := rclone.GetJobReply(job)
+			if err != nil || jobInfo.Error != "" {
+				jobErr = jobInfo.Error
+				break
+			}
+
+			refreshItems := make([]ListItem, 0, len(dir.Empty))
+			for _, item := range dir.Empty {
+				item.RefreshAddItem = false
+				refreshItems = append(refreshItems, item)
+			}
+
+			job.RefreshItems = refreshItems
+
+			rclone.StopJob(job, jobInfo.Error)
+		}
+
+		rclone.StopJob(mainJob, jobErr, struct{}{})
+	}()
+
+	return mainJob
+}
@@ -0,0 +1,476 @@
+package rclone
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/darkhz/rclone-tui/rclone"
+)
+
+// Schedule stores information about a scheduled operation.
+type Schedule struct {
+	ID        int64      `json:"id"`
+	Operation string     `json:"operation"`
+	Cron      string     `json:"cron,omitempty"`
+	At        time.Time  `json:"at,omitempty"`
+	Items     []ListItem `json:"items"`
+	DstFs     string     `json:"dstFs,omitempty"`
+	DstRemote string     `json:"dstRemote,omitempty"`
+	Filter    *Filter    `json:"filter,omitempty"`
+	Preset    *Preset    `json:"preset,omitempty"`
+
+	NextRun    time.Time `json:"nextRun,omitempty"`
+	LastRun    time.Time `json:"lastRun,omitempty"`
+	LastResult string    `json:"lastResult,omitempty"`
+
+	running bool
+}
+
+// cronField stores the allowed values for a single cron field.
+type cronField map[int]struct{}
+
+// cronSchedule stores a parsed cron expression.
+type cronSchedule struct {
+	minute, hour, dom, month, dow cronField
+
+	domAny, dowAny bool
+}
+
+const scheduleTimeFormat = "2006-01-02 15:04"
+
+var (
+	schedules     = newJSONStore[Schedule]("schedules")
+	schedulerOnce sync.Once
+
+	cronDescriptors = map[string]string{
+		"@hourly":  "0 * * * *",
+		"@daily":   "0 0 * * *",
+		"@weekly":  "0 0 * * 0",
+		"@monthly": "0 0 1 * *",
+		"@yearly":  "0 0 1 1 *",
+	}
+)
+
+// LoadSchedules loads the schedules from the provided file.
+func LoadSchedules(file string) error {
+	if err := schedules.load(file); err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	return schedules.update(func(entries []Schedule) ([]Schedule, bool) {
+		for i, s := range entries {
+			if s.Cron != "" && (s.NextRun.IsZero() || s.NextRun.Before(now)) {
+				entries[i].NextRun, _ = nextScheduleRun(s, now)
+			}
+		}
+
+		return entries, false
+	})
+}
+
+// StartScheduler starts running the loaded schedules in the background.
+// Errors which occur while saving the schedules are passed to onError.
+func StartScheduler(onError func(err error)) {
+	schedulerOnce.Do(func() {
+		go func() {
+			t := time.NewTicker(10 * time.Second)
+			defer t.Stop()
+
+			for {
+				if err := runDueSchedules(time.Now(), onError); err != nil {
+					onError(err)
+				}
+
+				<-t.C
+			}
+		}()
+	})
+}
+
+// GetSchedules returns the list of schedules, sorted by their next run.
+func GetSchedules() []Schedule {
+	list := schedules.list()
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].NextRun.IsZero() != list[j].NextRun.IsZero() {
+			return !list[i].NextRun.IsZero()
+		}
+
+		return list[i].NextRun.Before(list[j].NextRun)
+	})
+
+	return list
+}
+
+// AddSchedule parses the schedule specification, which is either a cron expression
+// or a time in the "YYYY-MM-DD HH:MM" format, and adds the operation to the schedules.
+// If options are provided, they are applied to the operation whenever it runs.
+func AddSchedule(operation, spec string, items []ListItem, dstFs, dstRemote string, opts ...BatchOptions) (Schedule, error) {
+	schedule := Schedule{
+		Operation: operation,
+		Items:     items,
+		DstFs:     dstFs,
+		DstRemote: dstRemote,
+	}
+
+	if o := batchOptions(opts); !o.Filter.IsEmpty() {
+		schedule.Filter = &o.Filter
+	}
+	if o := batchOptions(opts); !o.Preset.IsEmpty() {
+		schedule.Preset = &o.Preset
+	}
+
+	if items == nil {
+		return Schedule{}, fmt.Errorf("No items selected")
+	}
+
+	switch operation {
+	case "Copy", "Move", "Sync", "Delete":
+
+	default:
+		return Schedule{}, fmt.Errorf("%s: Cannot schedule operation", operation)
+	}
+
+	spec = strings.TrimSpace(spec)
+	if at, err := time.ParseInLocation(scheduleTimeFormat, spec, time.Local); err == nil {
+		if at.Before(time.Now()) {
+			return Schedule{}, fmt.Errorf("%s: Time is in the past", spec)
+		}
+
+		schedule.At = at
+	} else {
+		schedule.Cron = spec
+	}
+
+	next, err := nextScheduleRun(schedule, time.Now())
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	schedule.NextRun = next
+
+	err = schedules.update(func(entries []Schedule) ([]Schedule, bool) {
+		for _, s := range entries {
+			if s.ID >= schedule.ID {
+				schedule.ID = s.ID + 1
+			}
+		}
+
+		return append(entries, schedule), true
+	})
+
+	return schedule, err
+}
+
+// RemoveSchedule removes the schedule with the provided ID.
+func RemoveSchedule(id int64) error {
+	found, err := schedules.remove(func(s Schedule) bool {
+		return s.ID == id
+	})
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("%d: No such schedule", id)
+	}
+
+	return nil
+}
+
+// Description returns a description of the scheduled operation.
+func (s Schedule) Description() string {
+	desc := s.Operation + " " + strconv.Itoa(len(s.Items)) + " item(s)"
+	if len(s.Items) == 1 {
+		desc = s.Operation + " " + s.Items[0].FS + s.Items[0].Path
+	}
+
+	if s.Operation != "Delete" {
+		desc += " -> " + s.DstFs + s.DstRemote
+	}
+
+	if s.Filter != nil {
+		desc += " [filter: " + s.Filter.Name + "]"
+	}
+	if s.Preset != nil {
+		desc += " [" + s.Preset.Label() + "]"
+	}
+
+	if s.Cron != "" {
+		desc += " (" + s.Cron + ")"
+	}
+
+	return desc
+}
+
+// runDueSchedules starts all schedules whose next run is due, and saves
+// the schedules if any of them were started.
+func runDueSchedules(now time.Time, onError func(err error)) error {
+	return schedules.update(func(entries []Schedule) ([]Schedule, bool) {
+		var started bool
+
+		for i, s := range entries {
+			if s.running || s.NextRun.IsZero() || s.NextRun.After(now) {
+				continue
+			}
+
+			entries[i].running = true
+			entries[i].LastRun = now
+			entries[i].NextRun, _ = nextScheduleRun(s, now)
+
+			started = true
+
+			go runSchedule(s, onError)
+		}
+
+		return entries, started
+	})
+}
+
+// runSchedule runs the scheduled operation and records its result.
+func runSchedule(s Schedule, onError func(err error)) {
+	var job *rclone.Job
+	var opts BatchOptions
+
+	result := "Success"
+
+	if s.Filter != nil {
+		opts.Filter = *s.Filter
+	}
+	if s.Preset != nil {
+		opts.Preset = *s.Preset
+	}
+
+	switch s.Operation {
+	case "Copy":
+		job = Copy(s.Items, s.DstFs, s.DstRemote, opts)
+
+	case "Move":
+		job = Move(s.Items, s.DstFs, s.DstRemote, opts)
+
+	case "Sync":
+		job = Sync(s.Items, s.DstFs, s.DstRemote, opts)
+
+	case "Delete":
+		job = Delete(s.Items, opts)
+	}
+
+	if job == nil {
+		result = "Failed: No job started"
+	} else if _, err := rclone.GetJobReply(job); err != nil {
+		result = "Failed: " + err.Error()
+	}
+
+	err := schedules.update(func(entries []Schedule) ([]Schedule, bool) {
+		for i := range entries {
+			if entries[i].ID != s.ID {
+				continue
+			}
+
+			entries[i].running = false
+			entries[i].LastResult = result
+
+			return entries, true
+		}
+
+		return entries, false
+	})
+	if err != nil {
+		onError(err)
+	}
+}
+
+// nextScheduleRun returns the next time the schedule should run after t.
+// A zero time is returned if the schedule will not run again.
+func nextScheduleRun(s Schedule, t time.Time) (time.Time, error) {
+	if s.Cron == "" {
+		if s.At.After(t) {
+			return s.At, nil
+		}
+
+		return time.Time{}, nil
+	}
+
+	cron, err := parseCron(s.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return cron.next(t), nil
+}
+
+// parseCron parses a five-field cron expression or a cron descriptor.
+func parseCron(expr string) (cronSchedule, error) {
+	var cron cronSchedule
+
+	if descriptor, ok := cronDescriptors[expr]; ok {
+		expr = descriptor
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return cronSchedule{}, fmt.Errorf("%s: Invalid schedule", expr)
+	}
+
+	for i, bounds := range [][]int{
+		{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 6},
+	} {
+		field, err := parseCronField(fields[i], bounds[0], bounds[1])
+		if err != nil {
+			return cronSchedule{}, fmt.Errorf("%s: Invalid schedule field '%s'", expr, fields[i])
+		}
+
+		switch i {
+		case 0:
+			cron.minute = field
+
+		case 1:
+			cron.hour = field
+
+		case 2:
+			cron.dom = field
+			cron.domAny = fields[i] == "*"
+
+		case 3:
+			cron.month = field
+
+		case 4:
+			cron.dow = field
+			cron.dowAny = fields[i] == "*"
+		}
+	}
+
+	return cron, nil
+}
+
+// parseCronField parses a single cron field, which may contain
+// lists, ranges and steps.
+func parseCronField(field string, min, max int) (cronField, error) {
+	values := make(cronField)
+
+	for _, part := range strings.Split(field, ",") {
+		var err error
+
+		step := 1
+		start, end := min, max
+
+		if rangeStep := strings.SplitN(part, "/", 2); len(rangeStep) == 2 {
+			part = rangeStep[0]
+
+			step, err = strconv.Atoi(rangeStep[1])
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("Invalid step")
+			}
+		}
+
+		switch {
+		case part == "*":
+
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, err
+			}
+			if end, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, err
+			}
+
+		default:
+			if start, err = strconv.Atoi(part); err != nil {
+				return nil, err
+			}
+			if step == 1 {
+				end = start
+			}
+		}
+
+		// Both 0 and 7 are Sunday within the day of week field.
+		sunday := max == 6 && end == 7
+
+		if start < min || (end > max && !sunday) || start > end {
+			return nil, fmt.Errorf("Value out of range")
+		}
+
+		for v := start; v <= end; v += step {
+			if sunday && v == 7 {
+				values[0] = struct{}{}
+				continue
+			}
+
+			values[v] = struct{}{}
+		}
+	}
+
+	return values, nil
+}
+
+// next returns the next time after t which matches the cron expression.
+func (c cronSchedule) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if _, ok := c.month[int(t.Month())]; !ok {
+			t = wallClockAfter(t, t.Year(), t.Month()+1, 1, 0)
+			continue
+		}
+
+		if !c.matchDay(t) {
+			t = wallClockAfter(t, t.Year(), t.Month(), t.Day()+1, 0)
+			continue
+		}
+
+		if _, ok := c.hour[t.Hour()]; !ok {
+			t = wallClockAfter(t, t.Year(), t.Month(), t.Day(), t.Hour()+1)
+			continue
+		}
+
+		if _, ok := c.minute[t.Minute()]; !ok {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// wallClockAfter returns the start of the provided hour in t's location. If the
+// hour does not exist due to a daylight saving time change, and is normalized
+// to a time which is not after t, the start of the next hour is returned.
+func wallClockAfter(t time.Time, year int, month time.Month, day, hour int) time.Time {
+	next := time.Date(year, month, day, hour, 0, 0, 0, t.Location())
+	if !next.After(t) {
+		next = time.Date(year, month, day, hour+1, 0, 0, 0, t.Location())
+	}
+
+	return next
+}
+
+// matchDay checks whether the day of the provided time matches the expression.
+// Like cron, if both the day of month and day of week are restricted, either
+// of them may match.
+func (c cronSchedule) matchDay(t time.Time) bool {
+	_, dom := c.dom[t.Day()]
+	_, dow := c.dow[int(t.Weekday())]
+
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+
+	case c.domAny:
+		return dow
+
+	case c.dowAny:
+		return dom
+	}
+
+	return dom || dow
+}
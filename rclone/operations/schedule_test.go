@@ -0,0 +1,247 @@
+package rclone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		minutes []int
+		dows    []int
+		dowAny  bool
+		wantErr bool
+	}{
+		{expr: "@hourly", minutes: []int{0}, dowAny: true},
+		{expr: "*/15 * * * *", minutes: []int{0, 15, 30, 45}, dowAny: true},
+		{expr: "5,10-12 * * * 1-5", minutes: []int{5, 10, 11, 12}, dows: []int{1, 2, 3, 4, 5}},
+		{expr: "0 0 * * 7", minutes: []int{0}, dows: []int{0}},
+		{expr: "0 0 * * 5-7", minutes: []int{0}, dows: []int{0, 5, 6}},
+		{expr: "0 0 * * 5-7/2", minutes: []int{0}, dows: []int{0, 5}},
+		{expr: "0 0 * * 6-7/2", minutes: []int{0}, dows: []int{6}},
+		{expr: "* * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+		{expr: "5-1 * * * *", wantErr: true},
+		{expr: "0 24 * * *", wantErr: true},
+		{expr: "0 0 0 * *", wantErr: true},
+		{expr: "a * * * *", wantErr: true},
+	}
+
+	for _, test := range tests {
+		cron, err := parseCron(test.expr)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("parseCron(%q): expected an error", test.expr)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseCron(%q): %v", test.expr, err)
+			continue
+		}
+
+		if !fieldEquals(cron.minute, test.minutes) {
+			t.Errorf("parseCron(%q): minutes = %v, want %v", test.expr, cron.minute, test.minutes)
+		}
+		if cron.dowAny != test.dowAny {
+			t.Errorf("parseCron(%q): dowAny = %v, want %v", test.expr, cron.dowAny, test.dowAny)
+		}
+		if !test.dowAny && !fieldEquals(cron.dow, test.dows) {
+			t.Errorf("parseCron(%q): dows = %v, want %v", test.expr, cron.dow, test.dows)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	newYork := loadLocation(t, "America/New_York")
+
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{
+			expr: "*/15 * * * *",
+			from: time.Date(2026, 10, 16, 10, 7, 30, 0, time.UTC),
+			want: time.Date(2026, 10, 16, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			expr: "*/15 * * * *",
+			from: time.Date(2026, 10, 16, 10, 15, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 16, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			expr: "0 9 * * 1-5",
+			from: time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			expr: "0 0 31 * *",
+			from: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			expr: "0 12 13 * 5",
+			from: time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 13, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			expr: "@yearly",
+			from: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			expr: "0 0 29 2 *",
+			from: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			expr: "0 * * * *",
+			from: time.Date(2026, 3, 8, 1, 30, 0, 0, newYork),
+			want: time.Date(2026, 3, 8, 3, 0, 0, 0, newYork),
+		},
+		{
+			expr: "30 2 * * *",
+			from: time.Date(2026, 3, 7, 3, 0, 0, 0, newYork),
+			want: time.Date(2026, 3, 9, 2, 30, 0, 0, newYork),
+		},
+		{
+			expr: "30 1 * * *",
+			from: time.Date(2026, 11, 1, 0, 0, 0, 0, newYork),
+			want: time.Date(2026, 11, 1, 1, 30, 0, 0, newYork),
+		},
+		{
+			expr: "0 * * * *",
+			from: time.Date(2026, 11, 1, 1, 0, 0, 0, newYork),
+			want: time.Date(2026, 11, 1, 1, 0, 0, 0, newYork).Add(time.Hour),
+		},
+	}
+
+	for _, test := range tests {
+		cron, err := parseCron(test.expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q): %v", test.expr, err)
+		}
+
+		if got := cron.next(test.from); !got.Equal(test.want) {
+			t.Errorf("next(%q, %v) = %v, want %v", test.expr, test.from, got, test.want)
+		}
+	}
+}
+
+func TestCronNextAcrossDST(t *testing.T) {
+	for _, name := range []string{
+		"America/New_York", "Europe/London", "Australia/Sydney",
+		"America/Santiago", "Asia/Tehran",
+	} {
+		location := loadLocation(t, name)
+
+		for _, expr := range []string{"0 * * * *", "30 2 * * *", "0 0 * * *", "*/20 1-3 * * *"} {
+			cron, err := parseCron(expr)
+			if err != nil {
+				t.Fatalf("parseCron(%q): %v", expr, err)
+			}
+
+			from := time.Date(2026, 1, 1, 0, 0, 0, 0, location)
+			for i := 0; i < 2000; i++ {
+				next := cron.next(from)
+				if !next.After(from) {
+					t.Fatalf("%s: next(%q, %v) = %v, which is not after it", name, expr, from, next)
+				}
+
+				from = next
+			}
+		}
+	}
+}
+
+func TestWallClockAfter(t *testing.T) {
+	newYork := loadLocation(t, "America/New_York")
+
+	tests := []struct {
+		from time.Time
+		hour int
+		want time.Time
+	}{
+		{
+			from: time.Date(2026, 3, 8, 1, 59, 0, 0, newYork),
+			hour: 2,
+			want: time.Date(2026, 3, 8, 3, 0, 0, 0, newYork),
+		},
+		{
+			from: time.Date(2026, 3, 8, 0, 30, 0, 0, newYork),
+			hour: 1,
+			want: time.Date(2026, 3, 8, 1, 0, 0, 0, newYork),
+		},
+		{
+			from: time.Date(2026, 11, 1, 1, 0, 0, 0, newYork).Add(time.Hour),
+			hour: 1,
+			want: time.Date(2026, 11, 1, 2, 0, 0, 0, newYork),
+		},
+	}
+
+	for _, test := range tests {
+		got := wallClockAfter(test.from, test.from.Year(), test.from.Month(), test.from.Day(), test.hour)
+		if !got.Equal(test.want) {
+			t.Errorf("wallClockAfter(%v, %d) = %v, want %v", test.from, test.hour, got, test.want)
+		}
+	}
+}
+
+func TestRunDueSchedulesSavesOnlyWhenStarted(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schedules")
+	data := `[{"id":1,"operation":"Copy","cron":"0 0 1 1 *","items":[],"nextRun":"2099-01-01T00:00:00Z"}]`
+
+	if err := os.WriteFile(file, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadSchedules(file); err != nil {
+		t.Fatal(err)
+	}
+
+	onError := func(err error) {
+		t.Errorf("onError: %v", err)
+	}
+
+	if err := runDueSchedules(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), onError); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(saved) != data {
+		t.Errorf("schedules were saved without any schedule starting:\n%s", saved)
+	}
+}
+
+func fieldEquals(field cronField, values []int) bool {
+	if len(field) != len(values) {
+		return false
+	}
+
+	for _, v := range values {
+		if _, ok := field[v]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("%s: %v", name, err)
+	}
+
+	return location
+}
@@ -0,0 +1,198 @@
+package rclone
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/rclone-tui/rclone"
+	"github.com/mitchellh/mapstructure"
+)
+
+// SearchOptions stores the options for a recursive search.
+type SearchOptions struct {
+	Pattern string
+	Regex   bool
+
+	MinSize, MaxSize string
+	MinAge, MaxAge   string
+}
+
+// Search recursively searches the remote path for entries whose names match the
+// pattern, in a background job. The top-level directory is listed first, and then
+// each directory within it is listed recursively, and the matching entries from
+// each listing are sent to results as soon as they are found. Cancelling the job
+// stops the search without an error. The size and age
+// constraints are passed to rclone as filter rules, and only files are matched
+// if any of them are set.
+func Search(fs, remote string, opts SearchOptions, results func(items []ListItem)) (*rclone.Job, error) {
+	match, err := searchMatcher(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := make(map[string]interface{})
+	for key, value := range map[string]string{
+		"MinSize": opts.MinSize,
+		"MaxSize": opts.MaxSize,
+		"MinAge":  opts.MinAge,
+		"MaxAge":  opts.MaxAge,
+	} {
+		if value = strings.TrimSpace(value); value != "" {
+			filter[key] = value
+		}
+	}
+
+	if IsLocal(fs) && len(filter) > 0 {
+		return nil, fmt.Errorf("Size and age constraints cannot be used to search this machine")
+	}
+
+	id := rclone.GetNewJobID("Search")
+
+	mainJob := rclone.NewJob(
+		"Search", "Searching "+fs+remote, id,
+		"Search/"+strconv.FormatInt(id, 10),
+	)
+
+	rclone.AddJobToQueue(mainJob, struct{}{})
+
+	go func() {
+		var jobErr string
+
+		dirs := []string{remote}
+
+		for i := 0; i < len(dirs); i++ {
+			items, err := searchList(mainJob, fs, dirs[i], i > 0, filter)
+			if err != nil {
+				if mainJob.Context.Err() == nil {
+					jobErr = err.Error()
+				}
+
+				break
+			}
+
+			var matches []ListItem
+
+			for _, item := range items {
+				if i == 0 && item.IsDir {
+					dirs = append(dirs, item.Path)
+				}
+
+				if item.IsDir && len(filter) > 0 {
+					continue
+				}
+
+				if match(item.Name) {
+					matches = append(matches, item)
+				}
+			}
+
+			if matches != nil {
+				results(matches)
+			}
+		}
+
+		rclone.StopJob(mainJob, jobErr, struct{}{})
+	}()
+
+	return mainJob, nil
+}
+
+// searchMatcher returns a function which matches entry names with the search pattern.
+// Glob patterns are matched case-insensitively, and a pattern without any wildcards
+// matches names which contain it.
+func searchMatcher(opts SearchOptions) (func(name string) bool, error) {
+	pattern := strings.TrimSpace(opts.Pattern)
+	if pattern == "" {
+		return nil, fmt.Errorf("No search pattern specified")
+	}
+
+	if opts.Regex {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return re.MatchString, nil
+	}
+
+	pattern = strings.ToLower(pattern)
+	if !strings.ContainsAny(pattern, "*?[") {
+		pattern = "*" + pattern + "*"
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	return func(name string) bool {
+		matched, _ := path.Match(pattern, strings.ToLower(name))
+		return matched
+	}, nil
+}
+
+// searchList lists the entries within the remote path, recursively if recurse is set.
+func searchList(
+	mainJob *rclone.Job, fs, remote string,
+	recurse bool, filter map[string]interface{},
+) ([]ListItem, error) {
+	var list List
+
+	if IsLocal(fs) {
+		if err := mainJob.Context.Err(); err != nil {
+			return nil, err
+		}
+
+		if recurse {
+			items, err := listLocalRecursive(remote)
+			return applyDirSizes(List{Items: items}).Items, err
+		}
+
+		list, err := ListLocal(remote)
+		return applyDirSizes(list).Items, err
+	}
+
+	command := map[string]interface{}{
+		"fs":     fs,
+		"remote": remote,
+		"opt": map[string]interface{}{
+			"recurse": recurse,
+		},
+		"_group": mainJob.Group,
+	}
+	if len(filter) > 0 {
+		command["_filter"] = filter
+	}
+
+	job, err := rclone.SendCommandAsync(
+		"_Search", "Searching "+fs+remote,
+		command, "/operations/list", struct{}{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Group = mainJob.Group
+	job.Context = mainJob.Context
+	job.Cancel = mainJob.Cancel
+
+	go rclone.MonitorJob(job, struct{}{})
+
+	jobInfo, err := rclone.GetJobReply(job)
+	rclone.StopJob(job, jobInfo.Error)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mapstructure.Decode(jobInfo.Output, &list); err != nil {
+		return nil, err
+	}
+
+	for i, item := range list.Items {
+		list.Items[i] = appendItemDetails(item, fs)
+	}
+
+	return applyDirSizes(list).Items, nil
+}
@@ -0,0 +1,215 @@
+package rclone
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"code.cloudfoundry.org/bytefmt"
+	"github.com/darkhz/rclone-tui/rclone"
+	"github.com/mitchellh/mapstructure"
+)
+
+// DirSize stores the total size and the number of objects within a directory.
+type DirSize struct {
+	Count    int64 `mapstructure:"count"`
+	Bytes    int64 `mapstructure:"bytes"`
+	Sizeless int64 `mapstructure:"sizeless"`
+}
+
+var (
+	dirSizes          map[string]DirSize
+	dirSizesComputing map[string]struct{}
+	dirSizesLock      sync.Mutex
+)
+
+// applyDirSizes sets the cached sizes of the directories within the list.
+func applyDirSizes(list List) List {
+	for i, item := range list.Items {
+		if !item.IsDir {
+			continue
+		}
+
+		if size, ok := GetDirSize(item); ok {
+			list.Items[i] = SetDirSize(item, size)
+		}
+	}
+
+	return list
+}
+
+// GetDirSize returns the cached size of the directory, if it has been computed.
+func GetDirSize(item ListItem) (DirSize, bool) {
+	dirSizesLock.Lock()
+	defer dirSizesLock.Unlock()
+
+	size, ok := dirSizes[item.FS+item.Path]
+
+	return size, ok
+}
+
+// ComputeDirSizes recursively computes the sizes of the directories within the list
+// of items in a background job. Once the size of a directory has been computed, it is
+// cached and the directory is refreshed with its size and object count. Directories
+// whose sizes are already being computed are skipped.
+func ComputeDirSizes(items []ListItem) *rclone.Job {
+	var dirs []ListItem
+
+	dirSizesLock.Lock()
+	if dirSizesComputing == nil {
+		dirSizesComputing = make(map[string]struct{})
+	}
+
+	for _, item := range items {
+		if _, ok := dirSizesComputing[item.FS+item.Path]; item.IsDir && !ok {
+			dirs = append(dirs, item)
+			dirSizesComputing[item.FS+item.Path] = struct{}{}
+		}
+	}
+	dirSizesLock.Unlock()
+
+	if dirs == nil {
+		return nil
+	}
+
+	id := rclone.GetNewJobID("Size")
+
+	mainJob := rclone.NewJob(
+		"Size", "Computing directory sizes", id,
+		"Size/"+strconv.FormatInt(id, 10),
+	)
+
+	rclone.AddJobToQueue(mainJob, struct{}{})
+
+	go func() {
+		var jobErr string
+
+		defer func() {
+			dirSizesLock.Lock()
+			for _, dir := range dirs {
+				delete(dirSizesComputing, dir.FS+dir.Path)
+			}
+			dirSizesLock.Unlock()
+		}()
+
+		for i, dir := range dirs {
+			var size DirSize
+			var err error
+
+			description := "(" + strconv.Itoa(i+1) + "/" + strconv.Itoa(len(dirs)) + ") " +
+				"Computing size of " + dir.FS + dir.Path
+
+			if IsLocal(dir.FS) {
+				size, err = localDirSize(mainJob, dir)
+			} else {
+				size, err = remoteDirSize(mainJob, description, dir)
+			}
+			if err != nil {
+				jobErr = err.Error()
+				break
+			}
+
+			dirSizesLock.Lock()
+			if dirSizes == nil {
+				dirSizes = make(map[string]DirSize)
+			}
+			dirSizes[dir.FS+dir.Path] = size
+			dirSizesLock.Unlock()
+
+			dir = SetDirSize(dir, size)
+			dir.RefreshAddItem = true
+
+			rclone.PublishJobInfo(rclone.JobInfo{
+				Type:        "_Size",
+				Group:       mainJob.Group,
+				Description: description,
+				Finished:    true,
+
+				RefreshItems: []ListItem{dir},
+			})
+		}
+
+		rclone.StopJob(mainJob, jobErr, struct{}{})
+	}()
+
+	return mainJob
+}
+
+// SetDirSize sets the directory's size and object count.
+func SetDirSize(item ListItem, size DirSize) ListItem {
+	bytes := "0B"
+	if size.Bytes > 0 {
+		bytes = bytefmt.ByteSize(uint64(size.Bytes))
+	}
+
+	item.Size = size.Bytes
+	item.ISize = bytes + " (" + strconv.FormatInt(size.Count, 10) + ")"
+
+	return item
+}
+
+// remoteDirSize computes the size of a directory within a remote.
+func remoteDirSize(mainJob *rclone.Job, description string, dir ListItem) (DirSize, error) {
+	var size DirSize
+
+	command := map[string]interface{}{
+		"fs":     dir.FS + dir.Path,
+		"_group": mainJob.Group,
+	}
+
+	job, err := rclone.SendCommandAsync(
+		"_Size", description,
+		command, "/operations/size", struct{}{},
+	)
+	if err != nil {
+		return DirSize{}, err
+	}
+
+	job.Group = mainJob.Group
+	job.Context = mainJob.Context
+	job.Cancel = mainJob.Cancel
+
+	go rclone.MonitorJob(job, struct{}{})
+
+	jobInfo, err := rclone.GetJobReply(job)
+	rclone.StopJob(job, jobInfo.Error)
+	if err != nil {
+		return DirSize{}, err
+	}
+
+	err = mapstructure.Decode(jobInfo.Output, &size)
+
+	return size, err
+}
+
+// localDirSize computes the size of a directory within the local filesystem.
+func localDirSize(mainJob *rclone.Job, dir ListItem) (DirSize, error) {
+	var size DirSize
+
+	err := filepath.WalkDir(localPath(dir.Path), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := mainJob.Context.Err(); err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		size.Count++
+		size.Bytes += info.Size()
+
+		return nil
+	})
+
+	return size, err
+}
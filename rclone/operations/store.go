@@ -0,0 +1,139 @@
+package rclone
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// jsonStore stores a list of entries, which is loaded from and saved to
+// a JSON file within the config directory.
+type jsonStore[T any] struct {
+	name    string
+	file    string
+	entries []T
+
+	lock sync.Mutex
+}
+
+// newJSONStore returns a store for the provided type of entries.
+func newJSONStore[T any](name string) *jsonStore[T] {
+	return &jsonStore[T]{name: name}
+}
+
+// load loads the entries from the provided file.
+func (s *jsonStore[T]) load(file string) error {
+	var loaded []T
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("Cannot load %s from %s", s.name, file)
+	}
+
+	if len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &loaded); err != nil {
+			return fmt.Errorf("Cannot load %s from %s", s.name, file)
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.file = file
+	s.entries = loaded
+
+	return nil
+}
+
+// list returns a copy of the entries.
+func (s *jsonStore[T]) list() []T {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]T{}, s.entries...)
+}
+
+// find returns the first entry which matches.
+func (s *jsonStore[T]) find(match func(T) bool) (T, bool) {
+	var entry T
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, e := range s.entries {
+		if match(e) {
+			return e, true
+		}
+	}
+
+	return entry, false
+}
+
+// put replaces the first entry which matches, or adds the entry,
+// and saves the entries.
+func (s *jsonStore[T]) put(entry T, match func(T) bool) error {
+	return s.update(func(entries []T) ([]T, bool) {
+		for i, e := range entries {
+			if match(e) {
+				entries[i] = entry
+				return entries, true
+			}
+		}
+
+		return append(entries, entry), true
+	})
+}
+
+// remove removes the first entry which matches, and saves the entries.
+// It returns false if no entry matches.
+func (s *jsonStore[T]) remove(match func(T) bool) (bool, error) {
+	var found bool
+
+	err := s.update(func(entries []T) ([]T, bool) {
+		for i, e := range entries {
+			if match(e) {
+				found = true
+				return append(entries[:i], entries[i+1:]...), true
+			}
+		}
+
+		return entries, false
+	})
+
+	return found, err
+}
+
+// update modifies the entries with the provided function, which returns the
+// modified entries and whether they should be saved.
+func (s *jsonStore[T]) update(modify func(entries []T) ([]T, bool)) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	entries, changed := modify(s.entries)
+	s.entries = entries
+
+	if !changed {
+		return nil
+	}
+
+	return s.save()
+}
+
+// save saves the entries to the file. The store must be locked.
+func (s *jsonStore[T]) save() error {
+	if s.file == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.entries, "", "  ")
+	if err == nil {
+		err = os.WriteFile(s.file, data, 0600)
+	}
+	if err != nil {
+		return fmt.Errorf("Cannot save %s to %s", s.name, s.file)
+	}
+
+	return nil
+}
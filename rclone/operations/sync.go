@@ -0,0 +1,213 @@
+package rclone
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/rclone-tui/rclone"
+	"github.com/mitchellh/mapstructure"
+)
+
+// SyncChange stores a change which will be made by a sync operation.
+type SyncChange struct {
+	Action string
+	Name   string
+	Size   int64
+}
+
+// SyncPlan stores the changes which will be made by a sync operation. Since rclone
+// only keeps the last 100 completed transfers of a group, the changes may be
+// incomplete, in which case Truncated is set. Transfers and Deletes are always
+// the total number of files which will be transferred and deleted.
+type SyncPlan struct {
+	Changes []SyncChange
+
+	Transfers, Deletes int64
+	Truncated          bool
+}
+
+// SyncPreview runs a sync from the source to the destination without making any changes,
+// and returns the changes which would be made to the destination. The listings of the
+// source and the destination are used to determine whether a transferred file is a new
+// or an updated file, and whether a checked file is deleted.
+func SyncPreview(id, srcFs, dstFs string) (SyncPlan, error) {
+	var plan SyncPlan
+	var transfers, deletes int64
+
+	group := "SyncPreview/" + strconv.FormatInt(rclone.GetNewJobID("SyncPreview"), 10)
+
+	command := map[string]interface{}{
+		"srcFs":  srcFs,
+		"dstFs":  dstFs,
+		"_group": group,
+		"_config": map[string]interface{}{
+			"DryRun": true,
+		},
+	}
+
+	job, err := rclone.SendCommandAsync("UI:Explorer:"+id, "Previewing sync to "+dstFs, command, "/sync/sync")
+	if err != nil {
+		return SyncPlan{}, err
+	}
+	defer rclone.SendCommand(map[string]interface{}{"group": group}, "/core/stats-delete")
+
+	if _, err := rclone.GetJobReply(job); err != nil {
+		return SyncPlan{}, err
+	}
+
+	stats, err := rclone.GetGroupStats(group)
+	if err != nil {
+		return SyncPlan{}, err
+	}
+
+	transferred, err := rclone.GetTransferred(group)
+	if err != nil {
+		return SyncPlan{}, err
+	}
+
+	srcFiles, err := listFiles(id, srcFs)
+	if err != nil {
+		return SyncPlan{}, err
+	}
+
+	dstFiles, err := listFiles(id, dstFs)
+	if err != nil {
+		if !strings.Contains(err.Error(), "directory not found") {
+			return SyncPlan{}, err
+		}
+
+		dstFiles = make(map[string]struct{})
+	}
+
+	for _, transfer := range transferred {
+		var action string
+
+		switch {
+		case transfer.Checked:
+			_, inSrc := srcFiles[transfer.Name]
+
+			deleted := transfer.What == "deleting" || (transfer.What == "" && !inSrc)
+			if !deleted {
+				continue
+			}
+
+			action = "Delete"
+			deletes++
+
+		default:
+			action = "Copy"
+			if _, ok := dstFiles[transfer.Name]; ok {
+				action = "Update"
+			}
+
+			transfers++
+		}
+
+		plan.Changes = append(plan.Changes, SyncChange{
+			Action: action,
+			Name:   transfer.Name,
+			Size:   transfer.Size,
+		})
+	}
+
+	plan.Transfers, plan.Deletes = stats.Transfers, stats.Deletes
+	plan.Truncated = transfers < stats.Transfers || deletes < stats.Deletes
+
+	return plan, nil
+}
+
+// SyncDir syncs the source directory to the destination directory.
+func SyncDir(srcFs, dstFs string) *rclone.Job {
+	command := map[string]interface{}{
+		"srcFs": srcFs,
+		"dstFs": dstFs,
+	}
+
+	return startJob("Sync", "Syncing "+srcFs+" -> "+dstFs, "/sync/sync", command, nil)
+}
+
+// Bisync runs a bidirectional sync between the two paths with the provided options.
+// Once the bisync has completed, report is called with the list of changes and
+// conflicts which were found.
+func Bisync(path1, path2 string, opts map[string]interface{}, report func(changes []string)) *rclone.Job {
+	command := map[string]interface{}{
+		"path1": path1,
+		"path2": path2,
+	}
+
+	for key, value := range opts {
+		command[key] = value
+	}
+
+	return startJob(
+		"Bisync", "Bisyncing "+path1+" <-> "+path2, "/sync/bisync", command,
+		func(jobInfo rclone.JobInfo) []ListItem {
+			var changes []string
+
+			output, _ := jobInfo.Output["output"].(string)
+
+			for _, line := range strings.Split(output, "\n") {
+				if i := strings.Index(line, ": "); i >= 0 && strings.Contains(line[:i], "/") {
+					line = line[i+2:]
+				}
+
+				line = strings.TrimSpace(line)
+
+				for _, marker := range []string{
+					"Path1", "Path2", "conflict", "WARNING", "ERROR",
+				} {
+					if strings.Contains(line, marker) {
+						changes = append(changes, line)
+						break
+					}
+				}
+			}
+
+			if jobInfo.Error != "" {
+				changes = append(changes, "ERROR: "+jobInfo.Error)
+			}
+
+			report(changes)
+
+			return nil
+		},
+	)
+}
+
+// listFiles recursively lists all the files within the remote.
+func listFiles(id, fs string) (map[string]struct{}, error) {
+	var list List
+
+	files := make(map[string]struct{})
+
+	command := map[string]interface{}{
+		"fs":     fs,
+		"remote": "",
+		"opt": map[string]interface{}{
+			"recurse":    true,
+			"filesOnly":  true,
+			"noModTime":  true,
+			"noMimeType": true,
+		},
+	}
+
+	job, err := rclone.SendCommandAsync("UI:Explorer:"+id, "Listing "+fs, command, "/operations/list")
+	if err != nil {
+		return nil, err
+	}
+
+	jobInfo, err := rclone.GetJobReply(job)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mapstructure.Decode(jobInfo.Output, &list); err != nil {
+		return nil, err
+	}
+
+	for _, item := range list.Items {
+		files[item.Path] = struct{}{}
+	}
+
+	return files, nil
+}
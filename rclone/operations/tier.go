@@ -0,0 +1,49 @@
+package rclone
+
+import (
+	"fmt"
+
+	"github.com/darkhz/rclone-tui/rclone"
+)
+
+// storageTiers stores the storage tiers supported by each backend type.
+var storageTiers = map[string][]string{
+	"s3": {
+		"STANDARD", "REDUCED_REDUNDANCY", "STANDARD_IA", "ONEZONE_IA",
+		"INTELLIGENT_TIERING", "GLACIER", "GLACIER_IR", "DEEP_ARCHIVE",
+	},
+	"azureblob": {
+		"Hot", "Cool", "Cold", "Archive",
+	},
+	"oracleobjectstorage": {
+		"Standard", "InfrequentAccess", "Archive",
+	},
+}
+
+// StorageTiers returns the storage tiers which objects within the remote
+// can be moved to. If the remote supports changing tiers but its backend
+// type is not known, an empty list is returned.
+func StorageTiers(id, fs string) ([]string, error) {
+	fsinfo, err := FsInfo(id, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, feature := range fsinfo.FeatureList {
+		if feature == "SetTier" {
+			return storageTiers[RemoteType(fs)], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: Changing storage tiers is not supported", fs)
+}
+
+// SetTier changes the storage tier of a list of items. The tier of all
+// the objects within directories is changed.
+func SetTier(items []ListItem, tier string) *rclone.Job {
+	return BatchOperation(
+		"SetTier", "Changing tier of", "", "",
+		[]string{"/operations/settier", "/operations/settierfile"}, items,
+		BatchOptions{Tier: tier},
+	)
+}
@@ -0,0 +1,92 @@
+package rclone
+
+import (
+	"context"
+	"strings"
+
+	"github.com/darkhz/rclone-tui/rclone"
+)
+
+// TrashDetail stores information about the trash of a remote.
+type TrashDetail struct {
+	Size int64
+
+	CanCleanup bool
+	CanRestore bool
+}
+
+// TrashInfo returns the size of the trashed items within the remote, and
+// whether the trash can be cleaned up or restored from. The size is -1 if
+// the remote does not report it.
+func TrashInfo(id, fs string) (TrashDetail, error) {
+	trash := TrashDetail{Size: -1}
+
+	fsinfo, err := FsInfo(id, fs)
+	if err != nil {
+		return TrashDetail{}, err
+	}
+
+	for _, feature := range fsinfo.FeatureList {
+		if feature == "CleanUp" {
+			trash.CanCleanup = true
+			break
+		}
+	}
+
+	if about, err := AboutFS(rclone.GetClientContext(), fs); err == nil && about.Trashed > 0 {
+		trash.Size = about.Trashed
+	}
+
+	trash.CanRestore = RemoteType(fs) == "drive"
+
+	return trash, nil
+}
+
+// ListTrash returns the trashed items within the provided remote and path.
+func ListTrash(ctx context.Context, fs, path string) ([]ListItem, error) {
+	return listDir(ctx, trashFS(fs), path)
+}
+
+// CleanupTrash permanently deletes the trashed items within the remote.
+func CleanupTrash(fs string) *rclone.Job {
+	command := map[string]interface{}{
+		"fs": fs,
+	}
+
+	return startJob("Cleanup", "Cleaning up trash in "+fs, "/operations/cleanup", command, nil)
+}
+
+// RestoreTrash restores the trashed items within the provided remote and path,
+// and the directories within it.
+func RestoreTrash(fs, path string) *rclone.Job {
+	command := map[string]interface{}{
+		"command": "untrash",
+		"fs":      fs,
+		"arg":     []string{path},
+	}
+
+	return startJob(
+		"Restore", "Restoring trashed items in "+fs+path, "/backend/command", command,
+		func(jobInfo rclone.JobInfo) []ListItem {
+			if jobInfo.Error != "" {
+				return nil
+			}
+
+			items, err := listDir(rclone.GetClientContext(), fs, path)
+			if err != nil {
+				return nil
+			}
+
+			for i := range items {
+				items[i].RefreshAddItem = true
+			}
+
+			return items
+		},
+	)
+}
+
+// trashFS returns the remote with its trashed items only.
+func trashFS(fs string) string {
+	return strings.TrimSuffix(fs, ":") + ",trashed_only:"
+}
@@ -0,0 +1,232 @@
+package rclone
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+
+	"github.com/darkhz/rclone-tui/rclone"
+	"github.com/mitchellh/mapstructure"
+)
+
+// UsageEntry stores the disk usage of a directory entry. For directories, the
+// size and count are the total size and number of objects within the directory.
+type UsageEntry struct {
+	Item ListItem
+
+	Size, Count int64
+
+	Parent  *UsageEntry
+	Entries []*UsageEntry
+}
+
+// DiskUsage recursively lists the provided remote and path, and returns a tree of
+// the entries within it, with the entries of each directory sorted by their size.
+func DiskUsage(fs, path string) (*UsageEntry, error) {
+	var items []ListItem
+	var err error
+
+	if IsLocal(fs) {
+		items, err = listLocalRecursive(path)
+	} else {
+		items, err = listRemoteRecursive("Usage", "Analyzing disk usage of "+fs+path, fs, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	root := &UsageEntry{
+		Item: ListItem{
+			FS:    fs,
+			Name:  filepath.Base(path),
+			Path:  path,
+			IsDir: true,
+			Size:  -1,
+		},
+	}
+
+	dirs := map[string]*UsageEntry{path: root}
+
+	var getDir func(dirPath string) *UsageEntry
+	getDir = func(dirPath string) *UsageEntry {
+		if dir, ok := dirs[dirPath]; ok {
+			return dir
+		}
+		if dirPath == "" {
+			return root
+		}
+
+		parent := getDir(usageParentPath(dirPath))
+
+		dir := &UsageEntry{
+			Item: ListItem{
+				FS:    fs,
+				Name:  filepath.Base(dirPath),
+				Path:  dirPath,
+				IsDir: true,
+				Size:  -1,
+			},
+			Parent: parent,
+		}
+
+		dirs[dirPath] = dir
+		parent.Entries = append(parent.Entries, dir)
+
+		return dir
+	}
+
+	for _, item := range items {
+		if item.IsDir {
+			getDir(item.Path).Item = item
+			continue
+		}
+
+		parent := getDir(usageParentPath(item.Path))
+		parent.Entries = append(parent.Entries, &UsageEntry{
+			Item:   item,
+			Parent: parent,
+		})
+	}
+
+	root.update()
+
+	return root, nil
+}
+
+// Remove removes the entry from its parent, and updates the sizes of its parents.
+func (u *UsageEntry) Remove() {
+	parent := u.Parent
+	if parent == nil {
+		return
+	}
+
+	for i, entry := range parent.Entries {
+		if entry == u {
+			parent.Entries = append(parent.Entries[:i], parent.Entries[i+1:]...)
+			break
+		}
+	}
+
+	for ; parent != nil; parent = parent.Parent {
+		parent.Size -= u.Size
+		parent.Count -= u.Count
+	}
+}
+
+// update computes the sizes of the entry and the entries within it,
+// and sorts the entries by their size.
+func (u *UsageEntry) update() {
+	if !u.Item.IsDir {
+		u.Count = 1
+		if u.Item.Size > 0 {
+			u.Size = u.Item.Size
+		}
+
+		return
+	}
+
+	u.Size, u.Count = 0, 0
+
+	for _, entry := range u.Entries {
+		entry.update()
+
+		u.Size += entry.Size
+		u.Count += entry.Count
+	}
+
+	sort.SliceStable(u.Entries, func(i, j int) bool {
+		return u.Entries[i].Size > u.Entries[j].Size
+	})
+}
+
+// listRemoteRecursive recursively lists all the entries within the remote path,
+// and tracks the listing as a job with the provided name and description.
+func listRemoteRecursive(name, desc, fs, path string) ([]ListItem, error) {
+	var list List
+
+	command := map[string]interface{}{
+		"fs":     fs,
+		"remote": path,
+		"opt": map[string]interface{}{
+			"recurse":    true,
+			"noMimeType": true,
+		},
+	}
+
+	job, err := rclone.SendCommandAsync(name, desc, command, "/operations/list")
+	if err != nil {
+		return nil, err
+	}
+
+	jobInfo, err := rclone.GetJobReply(job)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mapstructure.Decode(jobInfo.Output, &list); err != nil {
+		return nil, err
+	}
+
+	for i, item := range list.Items {
+		list.Items[i] = appendItemDetails(item, fs)
+	}
+
+	return list.Items, nil
+}
+
+// listLocalRecursive recursively lists all the entries within the local path.
+func listLocalRecursive(path string) ([]ListItem, error) {
+	var items []ListItem
+
+	root := localPath(path)
+
+	err := filepath.WalkDir(root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if name == root {
+				return err
+			}
+
+			return nil
+		}
+
+		if name == root {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, name)
+		if err != nil {
+			return err
+		}
+
+		item := ListItem{
+			Name:  entry.Name(),
+			Path:  filepath.ToSlash(filepath.Join(path, rel)),
+			IsDir: entry.IsDir(),
+			Size:  info.Size(),
+		}
+		if item.IsDir {
+			item.Size = -1
+		}
+
+		items = append(items, appendItemDetails(item, LocalFS))
+
+		return nil
+	})
+
+	return items, err
+}
+
+// usageParentPath returns the path of the entry's parent directory.
+func usageParentPath(path string) string {
+	dir := filepath.ToSlash(filepath.Dir(path))
+	if dir == "." {
+		return ""
+	}
+
+	return dir
+}
@@ -0,0 +1,269 @@
+package rclone
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JobRecord stores information about a finished or running job.
+type JobRecord struct {
+	ID          int64     `json:"id"`
+	Type        string    `json:"type"`
+	Group       string    `json:"group"`
+	Description string    `json:"description"`
+	Error       string    `json:"error"`
+	StartTime   time.Time `json:"startTime"`
+	EndTime     time.Time `json:"endTime"`
+}
+
+// JobReport stores a report of all the items processed by a job.
+// Truncated is set if rclone did not keep all the processed items.
+type JobReport struct {
+	JobRecord
+
+	Items     []ReportItem `json:"items"`
+	Truncated bool         `json:"truncated"`
+}
+
+// ReportItem stores information about an item processed by a job.
+type ReportItem struct {
+	Name        string  `json:"name"`
+	Source      string  `json:"source"`
+	Destination string  `json:"destination"`
+	Size        int64   `json:"size"`
+	Duration    float64 `json:"duration"`
+	Result      string  `json:"result"`
+	Error       string  `json:"error"`
+}
+
+// TransferSnapshot stores information about a completed transfer.
+type TransferSnapshot struct {
+	Name        string    `json:"name"`
+	Size        int64     `json:"size"`
+	Bytes       int64     `json:"bytes"`
+	Checked     bool      `json:"checked"`
+	What        string    `json:"what"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+	Error       string    `json:"error"`
+	Group       string    `json:"group"`
+	SrcFs       string    `json:"srcFs"`
+	DstFs       string    `json:"dstFs"`
+}
+
+const maxJobHistory = 50
+
+// transferResults maps the actions which rclone reports for
+// completed transfers to the results shown in job reports.
+var transferResults = map[string]string{
+	"checking":  "Checked",
+	"deleting":  "Deleted",
+	"hashing":   "Hashed",
+	"importing": "Imported",
+	"merging":   "Merged",
+	"moving":    "Moved",
+	"renaming":  "Renamed",
+}
+
+var (
+	jobHistory     []JobRecord
+	jobHistoryLock sync.Mutex
+)
+
+// Record returns the job's information as a job record.
+func (j *Job) Record() JobRecord {
+	return JobRecord{
+		ID:          j.ID,
+		Type:        j.Type,
+		Group:       j.Group,
+		Description: j.Description,
+		StartTime:   j.StartTime,
+	}
+}
+
+// GetJobHistory returns the list of finished jobs, with the latest job first.
+func GetJobHistory() []JobRecord {
+	jobHistoryLock.Lock()
+	defer jobHistoryLock.Unlock()
+
+	history := make([]JobRecord, 0, len(jobHistory))
+	for i := len(jobHistory) - 1; i >= 0; i-- {
+		history = append(history, jobHistory[i])
+	}
+
+	return history
+}
+
+// GetTransferred returns the list of completed transfers for the provided group.
+func GetTransferred(group string) ([]TransferSnapshot, error) {
+	var transferred struct {
+		Transferred []TransferSnapshot `json:"transferred"`
+	}
+
+	command := map[string]interface{}{}
+	if group != "" {
+		command["group"] = group
+	}
+
+	response, err := SendCommand(command, "/core/transferred")
+	if err != nil {
+		return nil, err
+	}
+
+	err = response.Decode(&transferred)
+
+	return transferred.Transferred, err
+}
+
+// GroupStats stores the number of items processed by a group.
+type GroupStats struct {
+	Checks    int64 `json:"checks"`
+	Deletes   int64 `json:"deletes"`
+	Transfers int64 `json:"transfers"`
+}
+
+// GetGroupStats returns the number of items processed by the provided group.
+func GetGroupStats(group string) (GroupStats, error) {
+	var stats GroupStats
+
+	response, err := SendCommand(map[string]interface{}{"group": group}, "/core/stats")
+	if err != nil {
+		return GroupStats{}, err
+	}
+
+	err = response.Decode(&stats)
+
+	return stats, err
+}
+
+// NewJobReport returns a report of the items processed by the job. Since rclone
+// only keeps the last 100 completed transfers of a group, the report is marked
+// as truncated if the group's stats show that more items were processed.
+func NewJobReport(record JobRecord) (JobReport, error) {
+	report := JobReport{JobRecord: record}
+
+	transferred, err := GetTransferred(record.Group)
+	if err != nil {
+		return JobReport{}, err
+	}
+
+	if stats, err := GetGroupStats(record.Group); err == nil {
+		report.Truncated = int64(len(transferred)) < stats.Transfers+stats.Checks
+	}
+
+	for _, transfer := range transferred {
+		result := "Success"
+
+		switch {
+		case transfer.Error != "":
+			result = "Failed"
+
+		case transferResults[transfer.What] != "":
+			result = transferResults[transfer.What]
+
+		case transfer.Checked:
+			result = "Checked"
+		}
+
+		source := transfer.Name
+		if transfer.SrcFs != "" {
+			source = joinFsPath(transfer.SrcFs, transfer.Name)
+		}
+
+		destination := ""
+		if transfer.DstFs != "" {
+			destination = joinFsPath(transfer.DstFs, transfer.Name)
+		}
+
+		var duration float64
+		if !transfer.CompletedAt.IsZero() && !transfer.StartedAt.IsZero() {
+			duration = transfer.CompletedAt.Sub(transfer.StartedAt).Seconds()
+		}
+
+		report.Items = append(report.Items, ReportItem{
+			Name:        transfer.Name,
+			Source:      source,
+			Destination: destination,
+			Size:        transfer.Size,
+			Duration:    duration,
+			Result:      result,
+			Error:       transfer.Error,
+		})
+	}
+
+	return report, nil
+}
+
+// Export writes the report to the provided path. The report is written in the
+// CSV format if the path has a ".csv" extension, and in the JSON format otherwise.
+func (r JobReport) Export(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if strings.ToLower(filepath.Ext(path)) != ".csv" {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(r)
+	}
+
+	w := csv.NewWriter(file)
+
+	w.Write([]string{
+		"Name", "Source", "Destination", "Size", "Duration", "Result", "Error",
+	})
+
+	for _, item := range r.Items {
+		w.Write([]string{
+			item.Name,
+			item.Source,
+			item.Destination,
+			strconv.FormatInt(item.Size, 10),
+			strconv.FormatFloat(item.Duration, 'f', 3, 64),
+			item.Result,
+			item.Error,
+		})
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
+
+// joinFsPath joins the remote and the path.
+func joinFsPath(fs, path string) string {
+	if strings.HasSuffix(fs, ":") || strings.HasSuffix(fs, "/") {
+		return fs + path
+	}
+
+	return fs + "/" + path
+}
+
+// addJobHistory records the finished job in the job history.
+func addJobHistory(job *Job, errors string) {
+	if strings.HasPrefix(job.Type, "UI:") ||
+		strings.HasPrefix(job.Type, "_") {
+		return
+	}
+
+	record := job.Record()
+	record.Error = errors
+	record.EndTime = time.Now()
+
+	jobHistoryLock.Lock()
+	defer jobHistoryLock.Unlock()
+
+	jobHistory = append(jobHistory, record)
+	if len(jobHistory) > maxJobHistory {
+		jobHistory = jobHistory[len(jobHistory)-maxJobHistory:]
+	}
+}
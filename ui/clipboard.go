@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"encoding/base64"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/darkhz/rclone-tui/cmd"
+)
+
+// copyToClipboard copies the text to the system clipboard. If a clipboard command
+// is configured, the text is passed to its standard input, otherwise the OSC 52
+// escape sequence is sent to the terminal.
+func copyToClipboard(text string) {
+	if command := cmd.GetConfigProperty("clipboard"); command != "" {
+		var clipCmd *exec.Cmd
+
+		if runtime.GOOS == "windows" {
+			clipCmd = exec.Command("cmd", "/C", command)
+		} else {
+			clipCmd = exec.Command("sh", "-c", command)
+		}
+
+		clipCmd.Stdin = strings.NewReader(text)
+
+		if err := clipCmd.Run(); err != nil {
+			ErrorMessage("Clipboard", err)
+			return
+		}
+	} else {
+		writeTerminal("\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07")
+	}
+
+	InfoMessage("Copied to clipboard", false)
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"strconv"
+
+	"github.com/darkhz/rclone-tui/rclone"
+	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
+)
+
+// resolveConflicts checks whether the items already exist within the pane's
+// current directory, and prompts for the action to take for each conflicting item.
+// An uppercase reply applies the action to all the remaining items. It returns
+// false if the operation was cancelled.
+func (p *Pane) resolveConflicts(items []rcfns.ListItem) (map[rcfns.ListItem]rcfns.Conflict, bool) {
+	go p.startLoading("Checking " + p.FS + p.Path + " for existing items")
+	conflicts, err := rcfns.FindConflicts(rclone.GetClientContext(), items, p.FS, p.Path)
+	p.stopLoading()
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return nil, false
+	}
+
+	resolved := make(map[rcfns.ListItem]rcfns.Conflict, len(conflicts))
+
+	for i, conflict := range conflicts {
+		var all bool
+
+		label := conflict.Item.Name + " already exists"
+		if count := len(conflicts) - i; count > 1 {
+			label += " (" + strconv.Itoa(count) + " conflicts left, uppercase for all)"
+		}
+		label += ". (o)verwrite/(s)kip/keep (n)ewer/keep (b)oth?"
+
+		switch reply := SetInput(label); reply {
+		case "o", "O":
+			conflict.Action, all = rcfns.ConflictOverwrite, reply == "O"
+
+		case "s", "S":
+			conflict.Action, all = rcfns.ConflictSkip, reply == "S"
+
+		case "n", "N":
+			conflict.Action, all = rcfns.ConflictKeepNewer, reply == "N"
+
+		case "b", "B":
+			conflict.Action, all = rcfns.ConflictKeepBoth, reply == "B"
+
+		default:
+			return nil, false
+		}
+
+		if !all {
+			resolved[conflict.Item] = conflict
+			continue
+		}
+
+		for _, remaining := range conflicts[i:] {
+			remaining.Action = conflict.Action
+			resolved[remaining.Item] = remaining
+		}
+
+		break
+	}
+
+	return resolved, true
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// copyURL shows a form to download a URL into the pane's current directory.
+func (p *Pane) copyURL() {
+	var modal *Modal
+
+	if p.FS == "" {
+		return
+	}
+
+	if rcfns.IsLocal(p.FS) {
+		ErrorMessage("Explorer", fmt.Errorf("URLs cannot be downloaded to this machine"))
+		return
+	}
+
+	fs, path := p.FS, p.Path
+
+	params := map[string]interface{}{
+		"URL":  "",
+		"Name": "",
+	}
+
+	setData := func(name string, data interface{}) {
+		params[name] = data
+	}
+
+	form := NewForm()
+	form.SetButtonsAlign(tview.AlignCenter)
+	for _, label := range []string{"URL", "Name"} {
+		form.AddFormItem(
+			GetFormInputField(label, true, false, setData, func(label string) {}),
+		)
+	}
+	form.AddButton("Download", func() {
+		fileURL := strings.TrimSpace(params["URL"].(string))
+		if fileURL == "" {
+			return
+		}
+
+		modal.Exit()
+
+		go func() {
+			if _, err := rcfns.CopyURL(fs, path, fileURL, strings.TrimSpace(params["Name"].(string))); err != nil {
+				ErrorMessage("Explorer", err)
+			}
+		}()
+	})
+	form.AddButton("Cancel", func() {
+		modal.Exit()
+	})
+
+	modal = NewCustomModal("copyurl_form", form, form.GetFormItemCount()+10, 100)
+	modal.Flex.SetTitle("[::bu]Download URL to " + tview.Escape(fs+path))
+	modal.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			modal.Exit()
+		}
+
+		return event
+	})
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
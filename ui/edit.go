@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
+)
+
+// editFile downloads the file under the cursor to a temporary file, and opens it
+// in the editor specified by $EDITOR. If the file was changed, it is uploaded back
+// to the remote, after confirming whether to overwrite the remote file if it was
+// modified while it was being edited.
+func (p *Pane) editFile() {
+	if !p.Lock.TryAcquire(1) {
+		return
+	}
+	defer p.Lock.Release(1)
+
+	row, item, err := p.getSelection()
+	if err != nil || item.IsDir {
+		return
+	}
+
+	go p.startLoading("Downloading " + item.Name)
+	data, original, err := rcfns.ReadFile(item)
+	p.stopLoading()
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	edited, err := runEditor(item.Name, data)
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	if bytes.Equal(data, edited) {
+		InfoMessage(item.Name+" was not changed", false)
+		return
+	}
+
+	go p.startLoading("Checking " + item.Name + " for modifications")
+	modified, err := rcfns.FileModified(original)
+	p.stopLoading()
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	if modified && !ConfirmInput(item.Name+" was modified on the remote while editing, overwrite? (y/n)") {
+		return
+	}
+
+	go p.startLoading("Uploading " + item.Name)
+	updated, err := rcfns.WriteFile(item, edited)
+	p.stopLoading()
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	InfoMessage("Uploaded "+item.Name, false)
+
+	App.QueueUpdateDraw(func() {
+		for i, listItem := range p.list.Items {
+			if listItem.FS == updated.FS && listItem.Path == updated.Path {
+				p.list.Items[i] = updated
+				break
+			}
+		}
+
+		p.viewList(p.list)
+		p.View.Select(row, 0)
+	})
+}
+
+// runEditor suspends the application, and edits the data in a temporary file
+// with the editor specified by $EDITOR. The edited data is returned once the
+// editor has exited.
+func runEditor(name string, data []byte) ([]byte, error) {
+	var editErr error
+
+	file, err := os.CreateTemp("", "rclone-tui-*-"+filepath.Base(name))
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return nil, err
+	}
+	file.Close()
+
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if editor == nil {
+		if runtime.GOOS == "windows" {
+			editor = []string{"notepad"}
+		} else {
+			editor = []string{"vi"}
+		}
+	}
+
+	App.Suspend(func() {
+		editCmd := exec.Command(editor[0], append(editor[1:], file.Name())...)
+		editCmd.Stdin = os.Stdin
+		editCmd.Stdout = os.Stdout
+		editCmd.Stderr = os.Stderr
+
+		if err := editCmd.Run(); err != nil {
+			editErr = fmt.Errorf("%s: %s", editor[0], err.Error())
+		}
+	})
+
+	if editErr != nil {
+		return nil, editErr
+	}
+
+	return os.ReadFile(file.Name())
+}
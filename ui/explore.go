@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"code.cloudfoundry.org/bytefmt"
+	"github.com/darkhz/rclone-tui/cmd"
 	"github.com/darkhz/rclone-tui/rclone"
 	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
 	"github.com/darkhz/tview"
@@ -29,9 +32,9 @@ type ExplorerUI struct {
 	selections    map[rcfns.ListItem]struct{}
 	selectionLock sync.Mutex
 
-	init         bool
-	numPanes     int
-	refreshPanes chan rclone.JobInfo
+	init      bool
+	numPanes  int
+	showTiers bool
 }
 
 // Pane stores the layout for a single explorer pane.
@@ -60,13 +63,15 @@ type Pane struct {
 	sortMode string
 	sortAsc  bool
 
+	compare    map[string]string
+	compareDir string
+
 	aboutCtx     context.Context
 	remoteCtx    context.Context
 	aboutCancel  context.CancelFunc
 	remoteCancel context.CancelFunc
 
 	filtered, isloading bool
-	refreshChan         chan rclone.JobInfo
 }
 
 var explorer ExplorerUI
@@ -102,7 +107,6 @@ func (e *ExplorerUI) Exit(page string) bool {
 
 // Layout returns this page's layout.
 func (e *ExplorerUI) Layout() tview.Primitive {
-	e.refreshPanes = make(chan rclone.JobInfo, 10)
 	e.selections = make(map[rcfns.ListItem]struct{})
 
 	e.Flex = tview.NewFlex()
@@ -115,8 +119,6 @@ func (e *ExplorerUI) Layout() tview.Primitive {
 		e.numPanes = 2
 	}
 
-	go e.detectPaneRefresh()
-
 	for i := 0; i < e.numPanes; i++ {
 		title := tview.NewTextView()
 		title.SetDynamicColors(true)
@@ -164,11 +166,20 @@ func (e *ExplorerUI) Layout() tview.Primitive {
 			case ',':
 				e.getPane().Sort()
 
-			case 'p', 'm', 'd', 'M', ';', 'i':
+			case 'p', 'm', 'd', 'M', ';', 'i', 'S', 's', 'B', 'r', 'c', 'h', 'v', 'e', 'z', 'Z', 'U', 'f', 'o', 'T', 'E', 'u', 'y', 'Y', 't':
 				go e.getPane().Operation(event.Rune())
 
 			case ' ', 'a', 'A':
 				e.getPane().Select(event.Rune() == 'A', event.Rune() == 'a')
+
+			case 'C':
+				e.getPane().SelectDiffering()
+
+			case 'F':
+				go showFilters()
+
+			case 'I':
+				e.toggleTiers()
 			}
 
 			return event
@@ -240,8 +251,7 @@ func (e *ExplorerUI) Layout() tview.Primitive {
 			sortMode: "name",
 			sortAsc:  true,
 
-			savedPaths:  make(map[string]string),
-			refreshChan: make(chan rclone.JobInfo, 10),
+			savedPaths: make(map[string]string),
 		}
 
 		e.Panes = append(e.Panes, &pane)
@@ -311,6 +321,10 @@ StartListing:
 	App.QueueUpdateDraw(func() {
 		p.viewList(list)
 	})
+
+	if cmd.GetConfigProperty("dirsizes") != "" {
+		p.computeDirSizes(list.Items, true)
+	}
 }
 
 // ChangeDir changes the current directory.
@@ -362,7 +376,7 @@ func (p *Pane) ShowRemotes() {
 		return
 	}
 
-	remotes = append(remotes, "local")
+	remotes = append(remotes, "local", "this machine")
 
 	p.Modal = NewModal("show_remotes", "Select remote", true, false, len(remotes)+6, 60)
 	remoteInput, remoteTable := p.Modal.Input, p.Modal.Table
@@ -409,9 +423,14 @@ func (p *Pane) ShowRemotes() {
 
 			p.savedPaths[p.FS] = p.Path
 
-			if remote == "local" {
+			switch remote {
+			case "local":
 				fs = "/"
-			} else {
+
+			case "this machine":
+				fs = rcfns.LocalFS
+
+			default:
 				fs = remote + ":"
 			}
 
@@ -454,25 +473,87 @@ func (p *Pane) ShowRemotes() {
 func (p *Pane) Operation(key rune) {
 	switch key {
 	case 'p':
-		rcfns.Copy(explorer.getSelectionsList(), p.FS, p.Path)
-		go explorer.reloadPanes(true)
+		p.batchOperation("Copy", rcfns.BatchOptions{Filter: getActiveFilter()})
 
 	case 'm':
-		rcfns.Move(explorer.getSelectionsList(), p.FS, p.Path)
-		go explorer.reloadPanes(true)
+		p.batchOperation("Move", rcfns.BatchOptions{Filter: getActiveFilter()})
 
 	case 'd':
-		list := explorer.getSelectionsList()
-		if len(list) == 0 {
-			return
+		p.batchOperation("Delete", rcfns.BatchOptions{Filter: getActiveFilter()})
+
+	case 'o':
+		p.showPresets()
+
+	case 'S':
+		p.scheduleOperation()
+
+	case 's':
+		p.syncPreview()
+
+	case 'B':
+		p.bisync()
+
+	case 'r':
+		p.rename()
+
+	case 'c':
+		explorer.comparePanes()
+
+	case 'h':
+		p.showHashes()
+
+	case 'v':
+		p.showPreview()
+
+	case 'e':
+		p.editFile()
+
+	case 'z':
+		items := explorer.getSelectionsList()
+		if len(items) == 0 {
+			_, item, err := p.getSelection()
+			if err != nil {
+				return
+			}
+
+			items = append(items, item)
 		}
 
-		if !ConfirmInput("Delete selected files? (y/n)") {
+		p.computeDirSizes(items, false)
+
+	case 'Z':
+		p.computeDirSizes(p.list.Items, false)
+
+	case 'U':
+		p.showDiskUsage()
+
+	case 'f':
+		p.search()
+
+	case 'T':
+		p.showTrash()
+
+	case 'E':
+		p.removeEmptyDirs()
+
+	case 'u':
+		p.copyURL()
+
+	case 't':
+		p.setTier()
+
+	case 'y', 'Y':
+		_, item, err := p.getSelection()
+		if err != nil {
 			return
 		}
 
-		rcfns.Delete(explorer.getSelectionsList())
-		go explorer.reloadPanes(true)
+		path := item.Path
+		if key == 'Y' {
+			path = item.FS + item.Path
+		}
+
+		copyToClipboard(path)
 
 	case 'M':
 		if !p.Lock.TryAcquire(1) {
@@ -495,39 +576,7 @@ func (p *Pane) Operation(key rune) {
 		}
 
 	case ';':
-		if !p.Lock.TryAcquire(1) {
-			return
-		}
-
-		defer p.Lock.Release(1)
-		_, item, err := p.getSelection()
-		if err != nil {
-			return
-		}
-
-		go p.startLoading("Loading public link for " + item.Name)
-		defer p.stopLoading()
-
-		publiclink, err := rcfns.PublicLink(p.ID, p.FS, p.Path, item)
-		if err != nil {
-			ErrorMessage("Explorer", err, struct{}{})
-			return
-		}
-
-		modal := NewModal("public_link", "Public Link", false, true, 10, len(publiclink)+10)
-		modal.TextView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-			switch event.Key() {
-			case tcell.KeyEscape:
-				modal.Exit()
-			}
-
-			return event
-		})
-
-		App.QueueUpdateDraw(func() {
-			modal.TextView.SetText(publiclink)
-			modal.Show()
-		})
+		p.publicLink()
 
 	case 'i':
 		if !p.Lock.TryAcquire(1) {
@@ -539,6 +588,11 @@ func (p *Pane) Operation(key rune) {
 			return
 		}
 
+		if rcfns.IsLocal(p.FS) {
+			ErrorMessage("Explorer", fmt.Errorf("FS information is not available on this machine"))
+			return
+		}
+
 		go p.startLoading("Loading fs information for " + p.FS)
 		defer p.stopLoading()
 
@@ -555,6 +609,11 @@ func (p *Pane) Operation(key rune) {
 				modal.Exit()
 			}
 
+			switch event.Rune() {
+			case 'y':
+				go copyToClipboard(strings.TrimSpace(modal.TextView.GetText(true)))
+			}
+
 			return event
 		})
 
@@ -590,6 +649,685 @@ func (p *Pane) Operation(key rune) {
 	}
 }
 
+// batchOperation copies or moves the selected items to the current directory,
+// or deletes the selected items, with the provided options. If any of the items
+// already exist within the current directory, the action to take is prompted for.
+func (p *Pane) batchOperation(operation string, opts rcfns.BatchOptions) {
+	list := explorer.getSelectionsList()
+
+	switch operation {
+	case "Copy", "Move":
+		var ok bool
+
+		if len(list) == 0 || p.FS == "" {
+			return
+		}
+
+		local := rcfns.IsLocal(p.FS) || rcfns.IsLocal(list[0].FS)
+		if local && operation == "Move" {
+			ErrorMessage("Explorer", fmt.Errorf("Items can only be copied to or from this machine"))
+			return
+		}
+
+		if !local {
+			if opts.Conflicts, ok = p.resolveConflicts(list); !ok {
+				return
+			}
+		}
+
+		if operation == "Copy" {
+			rcfns.Copy(list, p.FS, p.Path, opts)
+		} else {
+			rcfns.Move(list, p.FS, p.Path, opts)
+		}
+
+	case "Delete":
+		if len(list) == 0 {
+			return
+		}
+
+		label := "Delete selected files? (y/n)"
+		if opts.Filter.Name != "" {
+			label = "Delete selected files matching filter " + opts.Filter.Name + "? (y/n)"
+		}
+		if opts.Preset.DryRun {
+			label = "Dry run: " + label
+		}
+
+		if !ConfirmInput(label) {
+			return
+		}
+
+		rcfns.Delete(list, opts)
+	}
+
+	go explorer.reloadPanes(true)
+}
+
+// scheduleOperation schedules an operation on the selected items,
+// with the current directory as the destination.
+func (p *Pane) scheduleOperation() {
+	var operation string
+
+	list := explorer.getSelectionsList()
+	if len(list) == 0 {
+		return
+	}
+
+	switch SetInput("Schedule (c)opy/(m)ove/(s)ync/(d)elete?") {
+	case "c":
+		operation = "Copy"
+
+	case "m":
+		operation = "Move"
+
+	case "s":
+		operation = "Sync"
+
+	case "d":
+		operation = "Delete"
+
+	default:
+		return
+	}
+
+	spec := SetInput("Schedule at (YYYY-MM-DD HH:MM or cron):", struct{}{})
+	if spec == "" {
+		return
+	}
+
+	schedule, err := rcfns.AddSchedule(
+		operation, spec, list, p.FS, p.Path,
+		rcfns.BatchOptions{Filter: getActiveFilter()},
+	)
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	InfoMessage("Scheduled "+operation+", next run at "+schedule.NextRun.Format("Mon 01/02 15:04"), false)
+
+	go explorer.reloadPanes(true)
+}
+
+// syncPreview shows the changes which will be made by syncing the current
+// directory to the other pane's directory, and starts the sync if confirmed.
+func (p *Pane) syncPreview() {
+	if !p.Lock.TryAcquire(1) {
+		return
+	}
+	defer p.Lock.Release(1)
+
+	dstPane := explorer.otherPane(p)
+	if p.FS == "" || dstPane.FS == "" {
+		return
+	}
+
+	srcFs, dstFs := p.FS+p.Path, dstPane.FS+dstPane.Path
+	if srcFs == dstFs {
+		ErrorMessage("Explorer", fmt.Errorf("Cannot sync a directory to itself"))
+		return
+	}
+
+	go p.startLoading("Previewing sync to " + dstFs)
+	defer p.stopLoading()
+
+	plan, err := rcfns.SyncPreview(p.ID, srcFs, dstFs)
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	changes := plan.Changes
+	if plan.Transfers == 0 && plan.Deletes == 0 {
+		InfoMessage(dstFs+" is already in sync", false)
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, change := range changes {
+		counts[change.Action]++
+	}
+
+	modal := NewModal(
+		"sync_preview", "Sync "+tview.Escape(srcFs)+" -> "+tview.Escape(dstFs),
+		false, false, len(changes)+10, 100,
+	)
+	modal.Table.SetSelectorWrap(false)
+	modal.Table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			modal.Exit()
+
+			go func() {
+				if !ConfirmInput("Start sync? This will modify " + dstFs + " (y/n)") {
+					return
+				}
+
+				job := rcfns.SyncDir(srcFs, dstFs)
+
+				go func() {
+					rclone.GetJobReply(job)
+					dstPane.List()
+				}()
+			}()
+
+		case tcell.KeyEscape:
+			modal.Exit()
+		}
+
+		return event
+	})
+
+	header := fmt.Sprintf(
+		"[::b]%d to copy, %d to update, [red::b]%d to delete[-:-:-] (Enter to sync, Escape to cancel)",
+		counts["Copy"], counts["Update"], counts["Delete"],
+	)
+	if plan.Truncated {
+		header = fmt.Sprintf(
+			"[::b]%d to transfer, [red::b]%d to delete[-:-:-], only the last %d are shown (Enter to sync, Escape to cancel)",
+			plan.Transfers, plan.Deletes, len(changes),
+		)
+	}
+
+	modal.Table.SetCell(0, 0, tview.NewTableCell(header).
+		SetExpansion(1).
+		SetSelectable(false),
+	)
+
+	for row, change := range changes {
+		var color tcell.Color
+
+		switch change.Action {
+		case "Copy":
+			color = tcell.ColorGreen
+
+		case "Update":
+			color = tcell.ColorYellow
+
+		case "Delete":
+			color = tcell.ColorRed
+		}
+
+		size := "-"
+		if change.Size >= 0 {
+			size = bytefmt.ByteSize(uint64(change.Size))
+		}
+
+		modal.Table.SetCell(row+1, 0, tview.NewTableCell(tview.Escape(change.Name)).
+			SetExpansion(1).
+			SetTextColor(color),
+		)
+		modal.Table.SetCell(row+1, 1, tview.NewTableCell(change.Action).
+			SetTextColor(color),
+		)
+		modal.Table.SetCell(row+1, 2, tview.NewTableCell(size).
+			SetAlign(tview.AlignRight).
+			SetTextColor(tcell.ColorGrey),
+		)
+	}
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
+
+// rename renames the selected items, or the item under the cursor if no items
+// are selected. Multiple items are renamed using a find/replace or a regular
+// expression pattern, and the new names are previewed before renaming.
+func (p *Pane) rename() {
+	if !p.Lock.TryAcquire(1) {
+		return
+	}
+	defer p.Lock.Release(1)
+
+	items := explorer.getSelectionsList()
+	if len(items) == 0 {
+		_, item, err := p.getSelection()
+		if err != nil {
+			return
+		}
+
+		newName := SetInput("Rename "+item.Name+" to:", struct{}{})
+		if newName == "" || newName == item.Name {
+			return
+		}
+
+		names := map[rcfns.ListItem]string{item: newName}
+		if err := validateRenames(p, []rcfns.ListItem{item}, names); err != nil {
+			ErrorMessage("Explorer", err)
+			return
+		}
+
+		rcfns.Rename([]rcfns.ListItem{item}, names)
+		go explorer.reloadPanes(true)
+
+		return
+	}
+
+	mode := SetInput("Rename (f)ind/replace or (r)egex?")
+	if mode != "f" && mode != "r" {
+		return
+	}
+
+	find := SetInput("Find:", struct{}{})
+	if find == "" {
+		return
+	}
+
+	replace := SetInput("Replace with:", struct{}{})
+
+	if mode == "f" {
+		find = regexp.QuoteMeta(find)
+		replace = strings.ReplaceAll(replace, "$", "$$")
+	}
+
+	pattern, err := regexp.Compile(find)
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Path < items[j].Path
+	})
+
+	names := make(map[rcfns.ListItem]string)
+	renames := make([]rcfns.ListItem, 0, len(items))
+
+	for _, item := range items {
+		newName := pattern.ReplaceAllString(item.Name, replace)
+		if newName == item.Name {
+			continue
+		}
+
+		names[item] = newName
+		renames = append(renames, item)
+	}
+
+	if len(renames) == 0 {
+		InfoMessage("No items match the pattern", false)
+		return
+	}
+
+	if err := validateRenames(p, renames, names); err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	modal := NewModal("rename_preview", "Rename", false, false, len(renames)+10, 100)
+	modal.Table.SetSelectorWrap(false)
+	modal.Table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			modal.Exit()
+
+			go func() {
+				rcfns.Rename(renames, names)
+				explorer.reloadPanes(true)
+			}()
+
+		case tcell.KeyEscape:
+			modal.Exit()
+		}
+
+		return event
+	})
+
+	modal.Table.SetCell(0, 0, tview.NewTableCell(fmt.Sprintf(
+		"[::b]%d items will be renamed (Enter to rename, Escape to cancel)", len(renames),
+	)).
+		SetSelectable(false),
+	)
+
+	for row, item := range renames {
+		modal.Table.SetCell(row+1, 0, tview.NewTableCell(tview.Escape(item.Name)).
+			SetExpansion(1),
+		)
+		modal.Table.SetCell(row+1, 1, tview.NewTableCell("->").
+			SetTextColor(tcell.ColorGrey),
+		)
+		modal.Table.SetCell(row+1, 2, tview.NewTableCell(tview.Escape(names[item])).
+			SetExpansion(1).
+			SetTextColor(tcell.ColorGreen),
+		)
+	}
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
+
+// validateRenames checks whether the new names are valid, and that the
+// renamed items will not overwrite each other or existing items.
+func validateRenames(p *Pane, items []rcfns.ListItem, names map[rcfns.ListItem]string) error {
+	existing := make(map[string]struct{})
+	for _, item := range p.list.Items {
+		existing[item.FS+item.Path] = struct{}{}
+	}
+
+	for _, item := range items {
+		newName := names[item]
+
+		if newName == "" || newName == "." || newName == ".." || strings.ContainsAny(newName, "/\\") {
+			return fmt.Errorf("Cannot rename %s to '%s'", item.Name, newName)
+		}
+
+		newPath := item.FS + filepath.Join(filepath.Dir(item.Path), newName)
+		if _, ok := existing[newPath]; ok {
+			return fmt.Errorf("Cannot rename %s, %s already exists", item.Name, newName)
+		}
+
+		existing[newPath] = struct{}{}
+	}
+
+	return nil
+}
+
+// bisync shows a form with the bisync options, and runs a bidirectional
+// sync between the current directory and the other pane's directory.
+func (p *Pane) bisync() {
+	var modal *Modal
+
+	otherPane := explorer.otherPane(p)
+	if p.FS == "" || otherPane.FS == "" {
+		return
+	}
+
+	path1, path2 := p.FS+p.Path, otherPane.FS+otherPane.Path
+	if path1 == path2 {
+		ErrorMessage("Explorer", fmt.Errorf("Cannot bisync a directory with itself"))
+		return
+	}
+
+	params := map[string]interface{}{
+		"Max Delete":       "50",
+		"Conflict Resolve": "none",
+		"Conflict Loser":   "num",
+	}
+
+	setData := func(name string, data interface{}) {
+		params[name] = data
+	}
+
+	form := NewForm()
+	form.SetButtonsAlign(tview.AlignCenter)
+	form.AddFormItem(
+		GetFormCheckBox("Dry Run", setData, func(label string) {}),
+	)
+	form.AddFormItem(
+		GetFormCheckBox("Resync", setData, func(label string) {}),
+	)
+	form.AddFormItem(
+		GetFormCheckBox("Check Access", setData, func(label string) {}),
+	)
+	form.AddFormItem(
+		GetFormCheckBox("Force", setData, func(label string) {}),
+	)
+	form.AddFormItem(
+		GetFormInputField("Max Delete", true, false, setData, func(label string) {}, "50"),
+	)
+	form.AddFormItem(
+		GetFormInputField("Conflict Resolve", true, false, setData, func(label string) {}, "none"),
+	)
+	form.AddFormItem(
+		GetFormInputField("Conflict Loser", true, false, setData, func(label string) {}, "num"),
+	)
+	form.AddButton("Start", func() {
+		opts, err := bisyncOptions(params)
+		if err != nil {
+			go ErrorMessage("Explorer", err)
+			return
+		}
+
+		modal.Exit()
+
+		go rcfns.Bisync(path1, path2, opts, func(changes []string) {
+			explorer.reloadPanes(false)
+			showBisyncChanges(path1, path2, changes)
+		})
+	})
+	form.AddButton("Cancel", func() {
+		modal.Exit()
+	})
+
+	modal = NewCustomModal("bisync_form", form, form.GetFormItemCount()+10, 100)
+	modal.Flex.SetTitle("[::bu]Bisync " + tview.Escape(path1) + " <-> " + tview.Escape(path2))
+	modal.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			modal.Exit()
+		}
+
+		return event
+	})
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
+
+// bisyncOptions converts the bisync form's data into bisync parameters.
+func bisyncOptions(params map[string]interface{}) (map[string]interface{}, error) {
+	opts := make(map[string]interface{})
+
+	for label, key := range map[string]string{
+		"Dry Run":      "dryRun",
+		"Resync":       "resync",
+		"Check Access": "checkAccess",
+		"Force":        "force",
+	} {
+		if checked, ok := params[label].(bool); ok && checked {
+			opts[key] = true
+		}
+	}
+
+	maxDelete, err := strconv.ParseInt(strings.TrimSpace(params["Max Delete"].(string)), 10, 64)
+	if err != nil || maxDelete < 0 || maxDelete > 100 {
+		return nil, fmt.Errorf("Max delete should be a percentage between 0 and 100")
+	}
+	opts["maxDelete"] = maxDelete
+
+	for _, option := range []struct {
+		label, key string
+		values     []string
+	}{
+		{"Conflict Resolve", "conflictResolve", []string{"none", "path1", "path2", "newer", "older", "larger", "smaller"}},
+		{"Conflict Loser", "conflictLoser", []string{"num", "pathname", "delete"}},
+	} {
+		value := strings.TrimSpace(params[option.label].(string))
+
+		for _, v := range option.values {
+			if value == v {
+				opts[option.key] = value
+				goto Next
+			}
+		}
+
+		return nil, fmt.Errorf("%s should be one of: %s", option.label, strings.Join(option.values, ", "))
+
+	Next:
+	}
+
+	return opts, nil
+}
+
+// showBisyncChanges shows the changes and conflicts which were found by a bisync.
+func showBisyncChanges(path1, path2 string, changes []string) {
+	modal := NewModal(
+		"bisync_changes", "Bisync "+tview.Escape(path1)+" <-> "+tview.Escape(path2),
+		false, true, len(changes)+10, 100,
+	)
+	modal.TextView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			modal.Exit()
+		}
+
+		return event
+	})
+
+	App.QueueUpdateDraw(func() {
+		if changes == nil {
+			modal.TextView.SetText("No changes were found")
+		}
+
+		for _, change := range changes {
+			color := "white"
+
+			switch {
+			case strings.Contains(change, "ERROR"):
+				color = "red"
+
+			case strings.Contains(change, "conflict"), strings.Contains(change, "WARNING"):
+				color = "yellow"
+			}
+
+			fmt.Fprintf(modal.TextView, "[%s]%s[-]\n", color, tview.Escape(change))
+		}
+
+		modal.Show()
+	})
+}
+
+// computeDirSizes starts computing the sizes of the directories within the items.
+// If auto is set, only the directories without a known size are computed.
+func (p *Pane) computeDirSizes(items []rcfns.ListItem, auto bool) {
+	var dirs []rcfns.ListItem
+
+	for _, item := range items {
+		if item.IsDir && (!auto || item.Size < 0) {
+			dirs = append(dirs, item)
+		}
+	}
+
+	rcfns.ComputeDirSizes(dirs)
+}
+
+// comparePanes compares the current directories of the left and right panes,
+// and marks the entries within both panes with the results.
+func (e *ExplorerUI) comparePanes() {
+	left, right := e.Panes[0], e.Panes[len(e.Panes)-1]
+	if left.FS == "" || right.FS == "" {
+		return
+	}
+
+	srcFs, dstFs := left.FS+left.Path, right.FS+right.Path
+	if srcFs == dstFs {
+		ErrorMessage("Explorer", fmt.Errorf("Cannot compare a directory with itself"))
+		return
+	}
+
+	download := ConfirmInput("Compare by downloading files? (y/n)")
+	oneway := ConfirmInput("Check files from the left pane only? (y/n)")
+
+	p := e.getPane()
+	if !p.Lock.TryAcquire(1) {
+		return
+	}
+	defer p.Lock.Release(1)
+
+	go p.startLoading("Comparing " + srcFs + " and " + dstFs)
+	defer p.stopLoading()
+
+	result, err := rcfns.Check(p.ID, srcFs, dstFs, download, oneway)
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	App.QueueUpdateDraw(func() {
+		for _, pane := range []*Pane{left, right} {
+			pane.compare = result.Entries
+			pane.compareDir = pane.FS + pane.Path
+
+			pane.viewList(pane.list)
+		}
+	})
+
+	summary := fmt.Sprintf(
+		"%d identical, %d differ, %d missing on left, %d missing on right",
+		result.Counts[rcfns.CheckIdentical], result.Counts[rcfns.CheckDiffer],
+		result.Counts[rcfns.CheckMissingOnSrc], result.Counts[rcfns.CheckMissingOnDst],
+	)
+	if count := result.Counts[rcfns.CheckError]; count > 0 {
+		summary += fmt.Sprintf(", %d errors", count)
+	}
+	if result.HashType != "" && !download {
+		summary += " (compared using " + result.HashType + ")"
+	}
+
+	if !result.Success {
+		ErrorMessage("Explorer", fmt.Errorf("%s: %s", result.Status, summary))
+		return
+	}
+
+	InfoMessage("Directories match: "+summary, false)
+}
+
+// SelectDiffering selects all the items within the current directory which
+// are different from, or missing within, the other pane's directory.
+func (p *Pane) SelectDiffering() {
+	if !p.Lock.TryAcquire(1) {
+		return
+	}
+	defer p.Lock.Release(1)
+
+	if p.compare == nil || p.compareDir != p.FS+p.Path {
+		return
+	}
+
+	for _, item := range p.list.Items {
+		switch p.compare[item.Name] {
+		case rcfns.CheckDiffer, rcfns.CheckMissingOnSrc, rcfns.CheckMissingOnDst:
+			p.itemSelected(item, true)
+		}
+	}
+
+	row, _ := p.View.GetSelection()
+
+	p.viewList(p.list)
+
+	p.View.Select(row, 0)
+}
+
+// nameColumn returns the column which displays the item names within the panes.
+// It follows the size and modified time columns, and the storage tier column if
+// it is shown. The compare marks are displayed in the column after it.
+func nameColumn() int {
+	if explorer.showTiers {
+		return 3
+	}
+
+	return 2
+}
+
+// compareMark returns the mark and its color for an item which has been compared.
+func (p *Pane) compareMark(item rcfns.ListItem) (string, tcell.Color) {
+	if p.compare == nil || p.compareDir != p.FS+p.Path {
+		return "", tcell.ColorDefault
+	}
+
+	switch p.compare[item.Name] {
+	case rcfns.CheckIdentical:
+		return "identical", tcell.ColorGreen
+
+	case rcfns.CheckDiffer:
+		return "differs", tcell.ColorYellow
+
+	case rcfns.CheckMissingOnSrc:
+		return "missing on left", tcell.ColorRed
+
+	case rcfns.CheckMissingOnDst:
+		return "missing on right", tcell.ColorRed
+
+	case rcfns.CheckError:
+		return "error", tcell.ColorRed
+	}
+
+	return "", tcell.ColorDefault
+}
+
 // Select selects multiple items within the current directory. The selected
 // items can then be used within an operation, for example copying files.
 func (p *Pane) Select(all, inverse bool) {
@@ -755,7 +1493,7 @@ func (p *Pane) viewList(list rcfns.List, selectRow ...int) {
 	p.View.Clear()
 
 	if title := p.FS + p.Path; title != "" {
-		p.Title.SetText("[::bu]" + tview.Escape(title))
+		p.Title.SetText("[::bu]" + tview.Escape(title) + filterTitle())
 		p.Title.ScrollToEnd()
 	}
 
@@ -801,7 +1539,19 @@ ShowList:
 				Bold(true),
 			),
 		)
-		p.View.SetCell(row, 2, tview.NewTableCell(tview.Escape(name)).
+		column := nameColumn()
+		if explorer.showTiers {
+			p.View.SetCell(row, column-1, tview.NewTableCell(item.Tier).
+				SetReference(item).
+				SetTextColor(infoColor).
+				SetBackgroundColor(tcell.ColorDefault).
+				SetSelectedStyle(tcell.Style{}.
+					Bold(true),
+				),
+			)
+		}
+
+		p.View.SetCell(row, column, tview.NewTableCell(tview.Escape(name)).
 			SetExpansion(1).
 			SetTextColor(itemColor).
 			SetAttributes(tcell.AttrBold).
@@ -812,6 +1562,16 @@ ShowList:
 			),
 		)
 
+		if mark, markColor := p.compareMark(item); mark != "" {
+			p.View.SetCell(row, column+1, tview.NewTableCell(mark).
+				SetTextColor(markColor).
+				SetBackgroundColor(tcell.ColorDefault).
+				SetSelectedStyle(tcell.Style{}.
+					Bold(true),
+				),
+			)
+		}
+
 		if selectRow != nil {
 			if rowCount := p.View.GetRowCount(); row >= rowCount-1 {
 				row = rowCount - 1
@@ -833,7 +1593,9 @@ ShowList:
 // watchItem watches for whether items in the current directory
 // have been added or removed.
 func (p *Pane) watchItem() {
-	for info := range p.refreshChan {
+	events := rclone.SubscribeJobEvents(0, true)
+
+	for info := range events.Events() {
 		if !info.Finished || info.Error != "" {
 			continue
 		}
@@ -860,10 +1622,13 @@ func (p *Pane) watchItem() {
 				}
 
 				for i, listItem := range p.list.Items {
-					if listItem.ID == item.ID || listItem.Name == item.Name {
+					if (item.ID != "" && listItem.ID == item.ID) || listItem.Name == item.Name {
 						if item.RefreshAddItem {
 							p.list.Items[i].Size = item.Size
 							p.list.Items[i].ISize = item.ISize
+							if item.Tier != "" {
+								p.list.Items[i].Tier = item.Tier
+							}
 
 							exist = true
 						} else {
@@ -1012,6 +1777,17 @@ func (e *ExplorerUI) getSelectionsList() []rcfns.ListItem {
 	return list
 }
 
+// otherPane returns the pane next to the provided pane.
+func (e *ExplorerUI) otherPane(p *Pane) *Pane {
+	for i, pane := range e.Panes {
+		if pane == p {
+			return e.Panes[(i+1)%len(e.Panes)]
+		}
+	}
+
+	return p
+}
+
 // getPane returns the currently focused pane.
 func (e *ExplorerUI) getPane() *Pane {
 	return explorer.Panes[explorer.currentPane]
@@ -1023,6 +1799,10 @@ func (e *ExplorerUI) reloadPanes(clearSelections bool) {
 		e.selectionLock.Lock()
 		e.selections = make(map[rcfns.ListItem]struct{})
 		e.selectionLock.Unlock()
+
+		for _, p := range e.Panes {
+			p.compare = nil
+		}
 	}
 
 	e.dataLock.Lock()
@@ -1085,21 +1865,3 @@ func (e *ExplorerUI) switchPanes(reverse bool) {
 
 	e.currentPane = currentPane
 }
-
-// detectPaneRefresh gets a signal and sends it to all the panes
-// so as to enable refreshing their current content.
-func (e *ExplorerUI) detectPaneRefresh() {
-	for jobInfo := range e.refreshPanes {
-		e.dataLock.Lock()
-
-		for _, pane := range e.Panes {
-			select {
-			case pane.refreshChan <- jobInfo:
-
-			default:
-			}
-		}
-
-		e.dataLock.Unlock()
-	}
-}
@@ -0,0 +1,292 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/darkhz/rclone-tui/cmd"
+	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+var (
+	activeFilter     rcfns.Filter
+	activeFilterLock sync.Mutex
+)
+
+// loadFilters loads the saved filter sets.
+func loadFilters() {
+	if err := cmd.LoadConfigFile("filters", rcfns.LoadFilters); err != nil {
+		ErrorMessage("Explorer", err)
+	}
+}
+
+// getActiveFilter returns the filter set which is applied to copy, move, delete
+// and scheduled operations.
+func getActiveFilter() rcfns.Filter {
+	activeFilterLock.Lock()
+	defer activeFilterLock.Unlock()
+
+	return activeFilter
+}
+
+// setActiveFilter sets the active filter set, and shows it in the pane titles.
+func setActiveFilter(filter rcfns.Filter) {
+	activeFilterLock.Lock()
+	activeFilter = filter
+	activeFilterLock.Unlock()
+
+	App.QueueUpdateDraw(func() {
+		for _, pane := range explorer.Panes {
+			if title := pane.FS + pane.Path; title != "" {
+				pane.Title.SetText("[::bu]" + tview.Escape(title) + filterTitle())
+				pane.Title.ScrollToEnd()
+			}
+		}
+	})
+}
+
+// filterTitle returns the name of the active filter set for the pane titles.
+func filterTitle() string {
+	filter := getActiveFilter()
+	if filter.Name == "" {
+		return ""
+	}
+
+	return "[-:-:-] [yellow::b](filter: " + tview.Escape(filter.Name) + ")[-:-:-]"
+}
+
+// showFilters shows the saved filter sets, which can be activated, created,
+// edited or removed.
+func showFilters() {
+	filters := rcfns.GetFilters()
+	active := getActiveFilter()
+
+	modal := NewModal("filters", "Filters", false, false, len(filters)+10, 100)
+	modal.Table.SetSelectorWrap(false)
+
+	getFilter := func() (rcfns.Filter, bool) {
+		row, _ := modal.Table.GetSelection()
+
+		filter, ok := modal.Table.GetCell(row, 0).GetReference().(rcfns.Filter)
+
+		return filter, ok
+	}
+
+	modal.Table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			filter, _ := getFilter()
+
+			modal.Exit()
+			go setActiveFilter(filter)
+
+			return nil
+
+		case tcell.KeyEscape:
+			modal.Exit()
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'n':
+			modal.Exit()
+			go editFilter(rcfns.Filter{})
+
+		case 'e':
+			if filter, ok := getFilter(); ok && filter.Name != "" {
+				modal.Exit()
+				go editFilter(filter)
+			}
+
+		case 'x':
+			filter, ok := getFilter()
+			if !ok || filter.Name == "" {
+				break
+			}
+
+			modal.Exit()
+
+			go func() {
+				if ConfirmInput("Remove filter " + filter.Name + "? (y/n)") {
+					if err := rcfns.RemoveFilter(filter.Name); err != nil {
+						ErrorMessage("Explorer", err)
+						return
+					}
+
+					if getActiveFilter().Name == filter.Name {
+						setActiveFilter(rcfns.Filter{})
+					}
+				}
+
+				showFilters()
+			}()
+		}
+
+		return event
+	})
+
+	modal.Table.SetCell(0, 0, tview.NewTableCell(
+		"[::b]Enter[-:-:-] activate, [::b]n[-:-:-] new, [::b]e[-:-:-] edit, [::b]x[-:-:-] remove",
+	).
+		SetSelectable(false),
+	)
+
+	for row, filter := range append([]rcfns.Filter{{}}, filters...) {
+		name, color := filter.Name, tcell.ColorWhite
+		if name == "" {
+			name = "No filter"
+		}
+		if filter.Name == active.Name {
+			name, color = "* "+name, tcell.ColorYellow
+		}
+
+		modal.Table.SetCell(row+1, 0, tview.NewTableCell(tview.Escape(name)).
+			SetTextColor(color).
+			SetReference(filter),
+		)
+		modal.Table.SetCell(row+1, 1, tview.NewTableCell(tview.Escape(filterDescription(filter))).
+			SetExpansion(1).
+			SetTextColor(tcell.ColorGrey),
+		)
+	}
+
+	modal.Table.Select(1, 0)
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
+
+// editFilter shows a form to create or edit a filter set.
+func editFilter(filter rcfns.Filter) {
+	var modal *Modal
+
+	params := map[string]interface{}{
+		"Name":        filter.Name,
+		"Include":     strings.Join(filter.IncludeRule, "; "),
+		"Exclude":     strings.Join(filter.ExcludeRule, "; "),
+		"Min Size":    filter.MinSize,
+		"Max Size":    filter.MaxSize,
+		"Min Age":     filter.MinAge,
+		"Max Age":     filter.MaxAge,
+		"Ignore Case": filter.IgnoreCase,
+	}
+
+	setData := func(name string, data interface{}) {
+		params[name] = data
+	}
+
+	form := NewForm()
+	form.SetButtonsAlign(tview.AlignCenter)
+	for _, label := range []string{"Name", "Include", "Exclude", "Min Size", "Max Size", "Min Age", "Max Age"} {
+		form.AddFormItem(
+			GetFormInputField(label, true, false, setData, func(label string) {}, params[label].(string)),
+		)
+	}
+	form.AddFormItem(
+		GetFormCheckBox("Ignore Case", setData, func(label string) {}, fmt.Sprint(filter.IgnoreCase)),
+	)
+	form.AddButton("Save", func() {
+		edited := rcfns.Filter{
+			Name:        strings.TrimSpace(params["Name"].(string)),
+			IncludeRule: rcfns.ParseFilterRules(params["Include"].(string)),
+			ExcludeRule: rcfns.ParseFilterRules(params["Exclude"].(string)),
+			MinSize:     strings.TrimSpace(params["Min Size"].(string)),
+			MaxSize:     strings.TrimSpace(params["Max Size"].(string)),
+			MinAge:      strings.TrimSpace(params["Min Age"].(string)),
+			MaxAge:      strings.TrimSpace(params["Max Age"].(string)),
+			IgnoreCase:  params["Ignore Case"].(bool),
+		}
+
+		if _, exists := rcfns.GetFilter(edited.Name); exists && edited.Name != filter.Name {
+			go ErrorMessage("Explorer", fmt.Errorf("%s: Filter already exists", edited.Name))
+			return
+		}
+
+		if err := rcfns.SaveFilter(edited); err != nil {
+			go ErrorMessage("Explorer", err)
+			return
+		}
+
+		if filter.Name != "" && filter.Name != edited.Name {
+			if err := rcfns.RemoveFilter(filter.Name); err != nil {
+				go ErrorMessage("Explorer", err)
+				return
+			}
+		}
+
+		modal.Exit()
+
+		go func() {
+			if active := getActiveFilter().Name; active != "" && (active == filter.Name || active == edited.Name) {
+				setActiveFilter(edited)
+			}
+
+			showFilters()
+		}()
+	})
+	form.AddButton("Cancel", func() {
+		modal.Exit()
+		go showFilters()
+	})
+
+	title := "New filter"
+	if filter.Name != "" {
+		title = "Edit filter " + filter.Name
+	}
+
+	modal = NewCustomModal("filter_form", form, form.GetFormItemCount()+10, 100)
+	modal.Flex.SetTitle("[::bu]" + tview.Escape(title))
+	modal.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			modal.Exit()
+			go showFilters()
+		}
+
+		return event
+	})
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
+
+// filterDescription returns a summary of the filter set's rules.
+func filterDescription(filter rcfns.Filter) string {
+	var desc []string
+
+	for _, rule := range []struct {
+		label string
+		rules []string
+	}{
+		{"include", filter.IncludeRule},
+		{"exclude", filter.ExcludeRule},
+	} {
+		if rule.rules != nil {
+			desc = append(desc, rule.label+": "+strings.Join(rule.rules, ", "))
+		}
+	}
+
+	for _, constraint := range []struct {
+		label, value string
+	}{
+		{"min size", filter.MinSize},
+		{"max size", filter.MaxSize},
+		{"min age", filter.MinAge},
+		{"max age", filter.MaxAge},
+	} {
+		if constraint.value != "" {
+			desc = append(desc, constraint.label+": "+constraint.value)
+		}
+	}
+
+	if filter.IgnoreCase && desc != nil {
+		desc = append(desc, "ignore case")
+	}
+
+	return strings.Join(desc, ", ")
+}
@@ -0,0 +1,254 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// showHashes computes the hashes of the selected items, or the item under the cursor
+// if no items are selected, and shows them in a modal. The hashes can then be verified
+// against a checksum file, or written to a checksum file within the current directory.
+func (p *Pane) showHashes() {
+	if !p.Lock.TryAcquire(1) {
+		return
+	}
+	defer p.Lock.Release(1)
+
+	if p.FS == "" {
+		return
+	}
+
+	items := explorer.getSelectionsList()
+	if len(items) == 0 {
+		_, item, err := p.getSelection()
+		if err != nil {
+			return
+		}
+
+		items = append(items, item)
+	}
+
+	for _, item := range items {
+		if rcfns.IsLocal(item.FS) {
+			ErrorMessage("Explorer", fmt.Errorf("Hashes cannot be computed on this machine"))
+			return
+		}
+	}
+
+	go p.startLoading("Loading hash types for " + p.FS)
+	fsinfo, err := rcfns.FsInfo(p.ID, p.FS)
+	p.stopLoading()
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	label := "Hash type (computed by downloading):"
+	if len(fsinfo.Hashes) > 0 {
+		label = "Hash type (" + strings.Join(fsinfo.Hashes, ", ") + "):"
+	}
+
+	hashType := strings.ToLower(strings.TrimSpace(SetInput(label, struct{}{})))
+	if hashType == "" {
+		return
+	}
+
+	download := true
+	for _, supported := range fsinfo.Hashes {
+		if strings.EqualFold(supported, hashType) {
+			download = false
+			break
+		}
+	}
+
+	go p.startLoading("Computing " + hashType + " hashes")
+	defer p.stopLoading()
+
+	hashes, err := rcfns.Hashsum(p.ID, items, hashType, download)
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	p.showHashModal(hashType, hashes, nil)
+}
+
+// showHashModal shows the hashes, and the results of verifying them if provided.
+func (p *Pane) showHashModal(hashType string, hashes []rcfns.FileHash, results []rcfns.HashResult) {
+	modal := NewModal("hashes", "Hashes ("+hashType+")", false, false, len(hashes)+10, 150)
+	modal.Table.SetSelectorWrap(false)
+	modal.Table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			modal.Exit()
+		}
+
+		switch event.Rune() {
+		case 'v':
+			modal.Exit()
+			go p.verifyHashes(hashType, hashes)
+
+		case 'w':
+			modal.Exit()
+			go p.writeHashes(hashes)
+
+		case 'y':
+			row, _ := modal.Table.GetSelection()
+			if row > 0 && row <= len(hashes) {
+				go copyToClipboard(hashes[row-1].Hash)
+			}
+		}
+
+		return event
+	})
+
+	header := "[::b]v[-:-:-] verify, [::b]w[-:-:-] write checksum file, [::b]y[-:-:-] copy hash"
+	if results != nil {
+		counts := make(map[string]int)
+		for _, result := range results {
+			counts[result.Result]++
+		}
+
+		header = fmt.Sprintf(
+			"[green::b]%d OK[-:-:-], [red::b]%d failed[-:-:-], [yellow::b]%d missing[-:-:-] (%s)",
+			counts["OK"], counts["FAILED"], counts["MISSING"], header,
+		)
+	}
+
+	modal.Table.SetCell(0, 0, tview.NewTableCell(header).
+		SetSelectable(false),
+	)
+
+	for row, fileHash := range hashes {
+		hashColor := tcell.ColorGrey
+
+		modal.Table.SetCell(row+1, 0, tview.NewTableCell(tview.Escape(fileHash.Name)).
+			SetExpansion(1),
+		)
+
+		if results != nil {
+			var resultColor tcell.Color
+
+			result := results[row]
+
+			switch result.Result {
+			case "OK":
+				resultColor = tcell.ColorGreen
+
+			case "FAILED":
+				resultColor, hashColor = tcell.ColorRed, tcell.ColorRed
+
+			case "MISSING":
+				resultColor = tcell.ColorYellow
+			}
+
+			modal.Table.SetCell(row+1, 2, tview.NewTableCell(result.Result).
+				SetTextColor(resultColor),
+			)
+		}
+
+		modal.Table.SetCell(row+1, 1, tview.NewTableCell(fileHash.Hash).
+			SetTextColor(hashColor),
+		)
+	}
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
+
+// verifyHashes verifies the hashes against pasted checksums or a checksum file.
+func (p *Pane) verifyHashes(hashType string, hashes []rcfns.FileHash) {
+	switch SetInput("Verify with (p)asted checksums or checksum (f)ile?") {
+	case "p":
+		p.pasteChecksums(hashType, hashes)
+
+	case "f":
+		path := SetInput("Checksum file:", struct{}{})
+		if path == "" {
+			goto ShowHashes
+		}
+
+		if !strings.Contains(path, ":") && !filepath.IsAbs(path) {
+			path = p.FS + filepath.Join(p.Path, path)
+		}
+
+		go p.startLoading("Loading checksums from " + path)
+		text, err := rcfns.Cat(path)
+		p.stopLoading()
+		if err != nil {
+			ErrorMessage("Explorer", err)
+			goto ShowHashes
+		}
+
+		p.showHashModal(hashType, hashes, rcfns.VerifyHashes(hashes, rcfns.ParseChecksums(text)))
+
+		return
+	}
+
+ShowHashes:
+	p.showHashModal(hashType, hashes, nil)
+}
+
+// pasteChecksums shows a text area to paste checksums into, and verifies the hashes
+// against the pasted checksums.
+func (p *Pane) pasteChecksums(hashType string, hashes []rcfns.FileHash) {
+	var modal *Modal
+
+	textArea := tview.NewTextArea()
+	textArea.SetPlaceholder("Paste checksums here, then press Ctrl+s to verify")
+	textArea.SetBackgroundColor(tcell.ColorDefault)
+	textArea.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyCtrlS:
+			modal.Exit()
+
+			checksums := rcfns.ParseChecksums(textArea.GetText())
+			go p.showHashModal(hashType, hashes, rcfns.VerifyHashes(hashes, checksums))
+
+			return nil
+
+		case tcell.KeyEscape:
+			modal.Exit()
+			go p.showHashModal(hashType, hashes, nil)
+		}
+
+		return event
+	})
+
+	modal = NewCustomModal("paste_checksums", textArea, 20, 150)
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
+
+// writeHashes writes the hashes to a checksum file within the current directory.
+func (p *Pane) writeHashes(hashes []rcfns.FileHash) {
+	name := SetInput("Write checksums to file:", struct{}{})
+	if name == "" {
+		return
+	}
+
+	if strings.ContainsAny(name, "/\\") {
+		ErrorMessage("Explorer", fmt.Errorf("Checksum file should be within the current directory"))
+		return
+	}
+
+	go p.startLoading("Writing checksums to " + name)
+	err := rcfns.WriteChecksums(p.FS, p.Path, name, hashes)
+	p.stopLoading()
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	InfoMessage("Checksums written to "+name, false)
+
+	p.List()
+}
@@ -64,8 +64,30 @@ var helpMap = map[string]map[string][]Help{
 			{"Move selected items", "m"},
 			{"Delete selected items", "d"},
 			{"Make directory", "M"},
-			{"Generate public link for item", ";"},
+			{"Manage public links for item", ";"},
 			{"Show remote information", "i"},
+			{"Schedule operation on selected items", "S"},
+			{"Preview and sync directory to other pane", "s"},
+			{"Bisync directory with other pane", "B"},
+			{"Rename item or selected items", "r"},
+			{"Compare left and right panes", "c"},
+			{"Select differing items after comparing", "C"},
+			{"Compute and verify hashes", "h"},
+			{"Preview file", "v"},
+			{"Edit file in $EDITOR", "e"},
+			{"Compute size of selected directories", "z"},
+			{"Compute size of all directories", "Z"},
+			{"Analyze disk usage of directory", "U"},
+			{"Search directory recursively", "f"},
+			{"Manage and apply filters", "F"},
+			{"Copy/move/delete with options", "o"},
+			{"Manage trash of remote", "T"},
+			{"Remove empty directories", "E"},
+			{"Download URL to directory", "u"},
+			{"Copy path of item", "y"},
+			{"Copy remote:path of item", "Y"},
+			{"Change storage tier of items", "t"},
+			{"Toggle storage tier column", "I"},
 		},
 	},
 	"Mounts": {
@@ -84,6 +106,9 @@ var helpMap = map[string]map[string][]Help{
 			{"Navigate between jobs", "Down/Up"},
 			{"Cancel job", "x"},
 			{"Cancel job group", "Ctrl+x"},
+			{"Remove scheduled job", "x"},
+			{"Export job report", "e"},
+			{"Copy job details", "y"},
 		},
 	},
 }
@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"code.cloudfoundry.org/bytefmt"
+	"github.com/darkhz/rclone-tui/cmd"
 	"github.com/darkhz/rclone-tui/rclone"
+	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
 )
@@ -23,27 +25,13 @@ type JobUI struct {
 	prevPage string
 }
 
-var (
-	jobUI JobUI
-
-	jobIndicator = make(chan rclone.JobInfo, 10)
-)
+var jobUI JobUI
 
 // JobMonitor monitors currently running jobs and displays them.
 func JobMonitor() {
-	for jobInfo := range rclone.JobInfoStatus() {
-		select {
-		case jobIndicator <- jobInfo:
-
-		default:
-		}
-
-		select {
-		case explorer.refreshPanes <- jobInfo:
-
-		default:
-		}
+	events := rclone.SubscribeJobEvents(0, true)
 
+	for jobInfo := range events.Events() {
 		if jobInfo.Error != "" {
 			ErrorMessage("Job Monitor", fmt.Errorf(jobInfo.Error))
 		}
@@ -78,8 +66,32 @@ func jobManager() *tview.TreeView {
 		switch event.Rune() {
 		case 'x':
 			node := jobUI.View.GetCurrentNode()
-			if job, ok := node.GetReference().(*rclone.Job); ok {
-				job.Cancel()
+			switch ref := node.GetReference().(type) {
+			case *rclone.Job:
+				ref.Cancel()
+
+			case rcfns.Schedule:
+				go removeSchedule(node, ref)
+			}
+
+		case 'e':
+			node := jobUI.View.GetCurrentNode()
+			switch ref := node.GetReference().(type) {
+			case *rclone.Job:
+				go exportJobReport(ref.Record())
+
+			case rclone.JobRecord:
+				go exportJobReport(ref)
+			}
+
+		case 'y':
+			node := jobUI.View.GetCurrentNode()
+			switch ref := node.GetReference().(type) {
+			case *rclone.Job:
+				go copyToClipboard(jobRecordText(ref.Record()))
+
+			case rclone.JobRecord:
+				go copyToClipboard(jobRecordText(ref))
 			}
 		}
 
@@ -108,7 +120,10 @@ func modifyJobNode(jobInfo rclone.JobInfo) {
 
 	for i, jobTypeNode := range rootNode.GetChildren() {
 		for _, jobNode := range jobTypeNode.GetChildren() {
-			job := jobNode.GetReference().(*rclone.Job)
+			job, ok := jobNode.GetReference().(*rclone.Job)
+			if !ok {
+				continue
+			}
 
 			if jobInfo.Group != "" {
 				typeID := strings.Split(jobInfo.Group, "/")
@@ -183,6 +198,146 @@ func updateJobNodeDetails(node *tview.TreeNode, jobInfo rclone.JobInfo) {
 	}
 }
 
+// scheduledJobsNode returns a node which lists the scheduled jobs.
+func scheduledJobsNode() *tview.TreeNode {
+	schedules := rcfns.GetSchedules()
+	if len(schedules) == 0 {
+		return nil
+	}
+
+	scheduleNode := tview.NewTreeNode("[::b]- [::bu]Scheduled")
+	scheduleNode.SetSelectable(false)
+	scheduleNode.SetColor(tcell.ColorPurple)
+
+	for _, schedule := range schedules {
+		nextRun, lastRun, lastResult := "-", "-", "-"
+
+		if !schedule.NextRun.IsZero() {
+			nextRun = schedule.NextRun.Format("Mon 01/02 15:04")
+		}
+		if !schedule.LastRun.IsZero() {
+			lastRun = schedule.LastRun.Format("Mon 01/02 15:04")
+		}
+		if schedule.LastResult != "" {
+			lastResult = schedule.LastResult
+		}
+
+		node := tview.NewTreeNode("[::b]" + tview.Escape(schedule.Description()))
+		node.SetReference(schedule)
+		node.SetColor(tcell.ColorGreen)
+
+		for _, state := range []string{
+			"Next run: " + nextRun,
+			"Last run: " + lastRun,
+			"Last result: " + lastResult,
+		} {
+			node.AddChild(tview.NewTreeNode(tview.Escape(state)).SetSelectable(false))
+		}
+
+		scheduleNode.AddChild(node)
+	}
+
+	return scheduleNode
+}
+
+// removeSchedule asks for confirmation before removing the schedule.
+func removeSchedule(node *tview.TreeNode, schedule rcfns.Schedule) {
+	if !ConfirmInput("Remove schedule? (y/n)") {
+		return
+	}
+
+	if err := rcfns.RemoveSchedule(schedule.ID); err != nil {
+		ErrorMessage("Job Manager", err)
+		return
+	}
+
+	App.QueueUpdateDraw(func() {
+		node.SetText("[::b]" + tview.Escape(schedule.Description()) + " (removed)")
+		node.SetReference(nil)
+		node.ClearChildren()
+	})
+}
+
+// jobHistoryNode returns a node which lists the finished jobs.
+func jobHistoryNode() *tview.TreeNode {
+	history := rclone.GetJobHistory()
+	if len(history) == 0 {
+		return nil
+	}
+
+	historyNode := tview.NewTreeNode("[::b]- [::bu]History")
+	historyNode.SetSelectable(false)
+	historyNode.SetColor(tcell.ColorPurple)
+
+	for _, record := range history {
+		result := "[green::b]Success"
+		if record.Error != "" {
+			result = "[red::b]Failed: " + tview.Escape(record.Error)
+		}
+
+		node := tview.NewTreeNode(
+			"[::b]" + record.Type + ": " + tview.Escape(record.Description) +
+				" (" + record.EndTime.Format("Mon 01/02 15:04") + ") " + result,
+		)
+		node.SetReference(record)
+		node.SetColor(tcell.ColorGrey)
+
+		historyNode.AddChild(node)
+	}
+
+	return historyNode
+}
+
+// jobRecordText returns the job's details as text.
+func jobRecordText(record rclone.JobRecord) string {
+	text := record.Type + ": " + record.Description
+	if record.Error != "" {
+		text += "\nError: " + record.Error
+	}
+
+	return text
+}
+
+// exportJobReport asks for a path, and exports a report of the job to it.
+func exportJobReport(record rclone.JobRecord) {
+	path := SetInput("Export report to (.json/.csv):", struct{}{})
+	if path == "" {
+		return
+	}
+
+	StartLoading("Exporting report for " + record.Description)
+
+	report, err := rclone.NewJobReport(record)
+	if err != nil {
+		ErrorMessage("Job Manager", err, struct{}{})
+		return
+	}
+
+	if err := report.Export(path); err != nil {
+		ErrorMessage("Job Manager", err, struct{}{})
+		return
+	}
+
+	if report.Truncated {
+		StopLoading("Exported incomplete report to " + path + ", rclone only keeps the last 100 transfers")
+		return
+	}
+
+	StopLoading("Exported report to " + path)
+}
+
+// startScheduler loads the saved schedules and starts the scheduler.
+func startScheduler() {
+	if err := cmd.LoadConfigFile("schedules", rcfns.LoadSchedules); err != nil {
+		ErrorMessage("Scheduler", err)
+		return
+	}
+
+	rcfns.StartScheduler(func(err error) {
+		ErrorMessage("Scheduler", err)
+	})
+}
+
 // openJobManager displays the job manager.
 func openJobManager() {
 	var rootNode *tview.TreeNode
@@ -229,6 +384,16 @@ SwitchToView:
 		return true
 	})
 
+	if scheduleNode := scheduledJobsNode(); scheduleNode != nil {
+		rootNode.AddChild(scheduleNode).AddChild(
+			tview.NewTreeNode("").SetSelectable(false),
+		)
+	}
+
+	if historyNode := jobHistoryNode(); historyNode != nil {
+		rootNode.AddChild(historyNode)
+	}
+
 	jobUI.prevPage, _ = MainPage.GetFrontPage()
 	MainPage.AddAndSwitchToPage("job_view", jobManager(), true)
 
@@ -0,0 +1,263 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/darkhz/rclone-tui/cmd"
+	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/skip2/go-qrcode"
+)
+
+// loadLinks loads the generated public links.
+func loadLinks() {
+	if err := cmd.LoadConfigFile("links", rcfns.LoadLinks); err != nil {
+		ErrorMessage("Explorer", err)
+	}
+}
+
+// publicLink shows a form to generate or remove a public link for the item under
+// the cursor, with an optional expiry duration.
+func (p *Pane) publicLink() {
+	var modal *Modal
+
+	if p.FS == "" {
+		return
+	}
+
+	if rcfns.IsLocal(p.FS) {
+		ErrorMessage("Explorer", fmt.Errorf("Public links cannot be created on this machine"))
+		return
+	}
+
+	_, item, err := p.getSelection()
+	if err != nil {
+		return
+	}
+
+	fs, path := p.FS, p.Path
+
+	params := map[string]interface{}{
+		"Expire": "",
+	}
+
+	setData := func(name string, data interface{}) {
+		params[name] = data
+	}
+
+	form := NewForm()
+	form.SetButtonsAlign(tview.AlignCenter)
+	form.AddFormItem(
+		GetFormInputField("Expire", true, false, setData, func(label string) {}),
+	)
+	form.AddButton("Create", func() {
+		modal.Exit()
+
+		go p.linkOperation(fs, path, item, rcfns.LinkOptions{
+			Expire: strings.TrimSpace(params["Expire"].(string)),
+		})
+	})
+	form.AddButton("Unlink", func() {
+		modal.Exit()
+
+		go func() {
+			if ConfirmInput("Remove public link for " + item.Name + "? (y/n)") {
+				p.linkOperation(fs, path, item, rcfns.LinkOptions{Unlink: true})
+			}
+		}()
+	})
+	form.AddButton("Links", func() {
+		modal.Exit()
+		go p.showLinks()
+	})
+	form.AddButton("Cancel", func() {
+		modal.Exit()
+	})
+
+	modal = NewCustomModal("link_form", form, form.GetFormItemCount()+10, 100)
+	modal.Flex.SetTitle("[::bu]Public link for " + tview.Escape(item.Name))
+	modal.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			modal.Exit()
+		}
+
+		return event
+	})
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
+
+// linkOperation generates or removes the public link for the item, and shows
+// the generated link.
+func (p *Pane) linkOperation(fs, path string, item rcfns.ListItem, opts rcfns.LinkOptions) {
+	if !p.Lock.TryAcquire(1) {
+		return
+	}
+
+	message := "Loading public link for " + item.Name
+	if opts.Unlink {
+		message = "Removing public link for " + item.Name
+	}
+
+	go p.startLoading(message)
+	link, err := rcfns.PublicLink(p.ID, fs, path, item, opts)
+	p.stopLoading()
+	p.Lock.Release(1)
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	if opts.Unlink {
+		InfoMessage("Removed public link for "+item.Name, false)
+		return
+	}
+
+	showLink(item.Name, link)
+}
+
+// showLinks shows the public links which were generated, which can be
+// shown again or removed.
+func (p *Pane) showLinks() {
+	links := rcfns.GetLinks()
+
+	modal := NewModal("links", "Public links", false, false, len(links)+10, 120)
+	modal.Table.SetSelectorWrap(false)
+
+	getLink := func() (rcfns.Link, bool) {
+		row, _ := modal.Table.GetSelection()
+
+		link, ok := modal.Table.GetCell(row, 0).GetReference().(rcfns.Link)
+
+		return link, ok
+	}
+
+	modal.Table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			if link, ok := getLink(); ok {
+				modal.Exit()
+				go showLink(filepath.Base(link.Path), link.URL)
+			}
+
+			return nil
+
+		case tcell.KeyEscape:
+			modal.Exit()
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'x':
+			link, ok := getLink()
+			if !ok {
+				break
+			}
+
+			modal.Exit()
+
+			go func() {
+				if ConfirmInput("Remove public link for " + link.FS + link.Path + "? (y/n)") {
+					item := rcfns.ListItem{Name: filepath.Base(link.Path)}
+					p.linkOperation(link.FS, filepath.Dir(link.Path), item, rcfns.LinkOptions{Unlink: true})
+				}
+
+				p.showLinks()
+			}()
+		}
+
+		return event
+	})
+
+	modal.Table.SetCell(0, 0, tview.NewTableCell(
+		"[::b]Enter[-:-:-] show, [::b]x[-:-:-] remove",
+	).
+		SetSelectable(false),
+	)
+
+	for row, link := range links {
+		modal.Table.SetCell(row+1, 0, tview.NewTableCell(tview.Escape(link.FS+link.Path)).
+			SetReference(link),
+		)
+		modal.Table.SetCell(row+1, 1, tview.NewTableCell(tview.Escape(link.URL)).
+			SetExpansion(1),
+		)
+		modal.Table.SetCell(row+1, 2, tview.NewTableCell(link.Description()).
+			SetTextColor(tcell.ColorGrey),
+		)
+	}
+
+	modal.Table.Select(1, 0)
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
+
+// showLink shows the public link, which can be copied to the clipboard,
+// or shown as a QR code.
+func showLink(name, link string) {
+	var showQR bool
+
+	qr, err := qrcode.New(link, qrcode.Low)
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	qrText := qr.ToSmallString(false)
+	qrLines := strings.Split(strings.TrimSuffix(qrText, "\n"), "\n")
+
+	height, width := 10, len(link)+10
+	if qrWidth := len([]rune(qrLines[0])) + 10; qrWidth > width {
+		width = qrWidth
+	}
+
+	modal := NewModal("public_link", "Public link for "+tview.Escape(name), false, true, height, width)
+
+	update := func() {
+		text := tview.Escape(link) + "\n\n[grey][::b]y[-:-:-][grey] copy, [::b]q[-:-:-][grey] toggle QR code[-]"
+		if showQR {
+			text += "\n\n[white:black]" + qrText + "[-:-]"
+		}
+
+		modal.TextView.SetText(text)
+	}
+
+	modal.TextView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			modal.Exit()
+		}
+
+		switch event.Rune() {
+		case 'y':
+			go copyToClipboard(link)
+
+		case 'q':
+			showQR = !showQR
+			update()
+
+			modal.height = height
+			if showQR {
+				modal.height += len(qrLines) + 2
+			}
+
+			modal.Exit()
+			modal.Show()
+		}
+
+		return event
+	})
+
+	App.QueueUpdateDraw(func() {
+		update()
+		modal.Show()
+	})
+}
@@ -234,6 +234,10 @@ func (m *MountsUI) managerUnmount() {
 
 		StopLoading("Unmounted" + mountpoint)
 
+		Notify(EventUnmount, "Unmounted "+mountpoint, map[string]string{
+			"MountPoint": mountpoint,
+		})
+
 		App.QueueUpdateDraw(func() {
 			m.formUI.ManagerTable.RemoveRow(row)
 			m.formUI.ManagerTable.Select(row, 0)
@@ -264,6 +268,8 @@ func (m *MountsUI) managerUnmountAll() {
 
 		StopLoading("Unmounted all mountpoints")
 
+		Notify(EventUnmount, "Unmounted all mountpoints", map[string]string{})
+
 		App.QueueUpdateDraw(func() {
 			for row := 1; row < m.formUI.ManagerTable.GetRowCount(); row++ {
 				m.formUI.ManagerTable.RemoveRow(row)
@@ -321,6 +327,11 @@ func (m *MountsUI) wizardCreateMount() {
 
 		StopLoading("Mounted " + loadText)
 
+		Notify(EventMount, "Mounted "+loadText, map[string]string{
+			"Fs":         fs,
+			"MountPoint": mountPoint,
+		})
+
 		m.wizardExit(false)
 	}()
 }
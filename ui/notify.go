@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/rclone-tui/cmd"
+	"github.com/darkhz/rclone-tui/rclone"
+)
+
+// Notification events which are sent to the hook command.
+const (
+	EventJobFinished    = "job-finished"
+	EventJobFailed      = "job-failed"
+	EventMount          = "mount"
+	EventUnmount        = "unmount"
+	EventConnectionLost = "connection-lost"
+)
+
+// notifiedJobs stores the jobs which were notified, so that
+// a job is notified, and the hook is run for it, only once.
+var notifiedJobs = make(map[string]struct{})
+
+// NotifyMonitor watches for job events, and sends notifications
+// for jobs which have finished or failed.
+func NotifyMonitor() {
+	events := rclone.SubscribeJobEvents(0, true)
+
+	for jobInfo := range events.Events() {
+		notifyJob(jobInfo)
+	}
+}
+
+// notifyJob sends notifications and runs the hook command
+// when a job has finished or failed. Directory size jobs, which
+// can be started automatically while exploring, are not notified.
+func notifyJob(jobInfo rclone.JobInfo) {
+	if !jobInfo.Finished ||
+		jobInfo.Type == "Size" ||
+		strings.HasPrefix(jobInfo.Type, "UI:") ||
+		strings.HasPrefix(jobInfo.Type, "_") {
+		return
+	}
+
+	key := jobInfo.Type + "/" + strconv.FormatInt(jobInfo.ID, 10)
+	if _, ok := notifiedJobs[key]; ok {
+		return
+	}
+	notifiedJobs[key] = struct{}{}
+
+	event, message := EventJobFinished, jobInfo.Description+" finished"
+	if jobInfo.Error != "" {
+		event, message = EventJobFailed, jobInfo.Description+" failed: "+jobInfo.Error
+	}
+
+	Notify(event, message, jobInfo)
+}
+
+// Notify sends the configured notifications with the provided message, and
+// runs the hook command with the event information.
+func Notify(event, message string, info interface{}) {
+	var notifications string
+
+	if cmd.GetConfigProperty("notify:bell") != "" {
+		notifications += "\a"
+	}
+	if cmd.GetConfigProperty("notify:osc9") != "" {
+		notifications += "\x1b]9;" + sanitizeNotification(message) + "\x07"
+	}
+	if cmd.GetConfigProperty("notify:osc777") != "" {
+		notifications += "\x1b]777;notify;rclone-tui;" + sanitizeNotification(message) + "\x07"
+	}
+
+	if notifications != "" {
+		writeTerminal(notifications)
+	}
+
+	if hook := cmd.GetConfigProperty("hook"); hook != "" {
+		go runHook(hook, event, info)
+	}
+}
+
+// runHook runs the hook command, and passes the event information
+// as JSON to its standard input.
+func runHook(hook, event string, info interface{}) {
+	var hookCmd *exec.Cmd
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		ErrorMessage("Hook", err)
+		return
+	}
+
+	if runtime.GOOS == "windows" {
+		hookCmd = exec.Command("cmd", "/C", hook)
+	} else {
+		hookCmd = exec.Command("sh", "-c", hook)
+	}
+
+	hookCmd.Stdin = bytes.NewReader(data)
+	hookCmd.Env = append(os.Environ(), "RCLONETUI_EVENT="+event)
+
+	if err := hookCmd.Run(); err != nil {
+		ErrorMessage("Hook", fmt.Errorf("%s: %s", event, err.Error()))
+	}
+}
+
+// sanitizeNotification removes characters which would terminate
+// the notification escape sequence.
+func sanitizeNotification(message string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return ' '
+		}
+
+		return r
+	}, message)
+}
@@ -0,0 +1,266 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/rclone-tui/cmd"
+	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// loadPresets loads the saved option presets.
+func loadPresets() {
+	if err := cmd.LoadConfigFile("presets", rcfns.LoadPresets); err != nil {
+		ErrorMessage("Explorer", err)
+	}
+}
+
+// showPresets shows the saved option presets. Selecting a preset shows the
+// options form, which starts an operation on the selected items with the options.
+func (p *Pane) showPresets() {
+	if len(explorer.getSelectionsList()) == 0 {
+		return
+	}
+
+	presets := rcfns.GetPresets()
+
+	modal := NewModal("presets", "Options", false, false, len(presets)+10, 100)
+	modal.Table.SetSelectorWrap(false)
+
+	getPreset := func() (rcfns.Preset, bool) {
+		row, _ := modal.Table.GetSelection()
+
+		preset, ok := modal.Table.GetCell(row, 0).GetReference().(rcfns.Preset)
+
+		return preset, ok
+	}
+
+	modal.Table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			preset, _ := getPreset()
+
+			modal.Exit()
+			go p.presetForm(preset)
+
+			return nil
+
+		case tcell.KeyEscape:
+			modal.Exit()
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'x':
+			preset, ok := getPreset()
+			if !ok || preset.Name == "" {
+				break
+			}
+
+			modal.Exit()
+
+			go func() {
+				if ConfirmInput("Remove preset " + preset.Name + "? (y/n)") {
+					if err := rcfns.RemovePreset(preset.Name); err != nil {
+						ErrorMessage("Explorer", err)
+						return
+					}
+				}
+
+				p.showPresets()
+			}()
+		}
+
+		return event
+	})
+
+	modal.Table.SetCell(0, 0, tview.NewTableCell(
+		"[::b]Enter[-:-:-] select, [::b]x[-:-:-] remove",
+	).
+		SetSelectable(false),
+	)
+
+	for row, preset := range append([]rcfns.Preset{{}}, presets...) {
+		name := preset.Name
+		if name == "" {
+			name = "Default options"
+		}
+
+		modal.Table.SetCell(row+1, 0, tview.NewTableCell(tview.Escape(name)).
+			SetReference(preset),
+		)
+		modal.Table.SetCell(row+1, 1, tview.NewTableCell(tview.Escape(presetDescription(preset))).
+			SetExpansion(1).
+			SetTextColor(tcell.ColorGrey),
+		)
+	}
+
+	modal.Table.Select(1, 0)
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
+
+// presetForm shows a form with the options from the preset, and starts an
+// operation on the selected items with the options. If a name is provided and
+// "Save Preset" is checked, the options are saved as a preset.
+func (p *Pane) presetForm(preset rcfns.Preset) {
+	var modal *Modal
+
+	params := map[string]interface{}{
+		"Name":            preset.Name,
+		"Transfers":       presetNumber(preset.Transfers),
+		"Checkers":        presetNumber(preset.Checkers),
+		"Bandwidth Limit": preset.BwLimitFile,
+		"Checksum":        preset.CheckSum,
+		"Ignore Existing": preset.IgnoreExisting,
+		"Update":          preset.UpdateOlder,
+		"No Traverse":     preset.NoTraverse,
+		"Dry Run":         preset.DryRun,
+		"Save Preset":     false,
+	}
+
+	setData := func(name string, data interface{}) {
+		params[name] = data
+	}
+
+	startOperation := func(operation string) {
+		edited, err := presetOptions(params)
+		if err != nil {
+			go ErrorMessage("Explorer", err)
+			return
+		}
+
+		if params["Save Preset"].(bool) {
+			if err := rcfns.SavePreset(edited); err != nil {
+				go ErrorMessage("Explorer", err)
+				return
+			}
+		}
+
+		modal.Exit()
+
+		go p.batchOperation(operation, rcfns.BatchOptions{
+			Filter: getActiveFilter(),
+			Preset: edited,
+		})
+	}
+
+	form := NewForm()
+	form.SetButtonsAlign(tview.AlignCenter)
+	for _, label := range []string{"Name", "Transfers", "Checkers", "Bandwidth Limit"} {
+		form.AddFormItem(
+			GetFormInputField(label, true, false, setData, func(label string) {}, params[label].(string)),
+		)
+	}
+	for _, label := range []string{"Checksum", "Ignore Existing", "Update", "No Traverse", "Dry Run", "Save Preset"} {
+		form.AddFormItem(
+			GetFormCheckBox(label, setData, func(label string) {}, fmt.Sprint(params[label])),
+		)
+	}
+	for _, operation := range []string{"Copy", "Move", "Delete"} {
+		operation := operation
+
+		form.AddButton(operation, func() {
+			startOperation(operation)
+		})
+	}
+	form.AddButton("Cancel", func() {
+		modal.Exit()
+	})
+
+	modal = NewCustomModal("preset_form", form, form.GetFormItemCount()+10, 100)
+	modal.Flex.SetTitle("[::bu]Options for " + strconv.Itoa(len(explorer.getSelectionsList())) + " selected items")
+	modal.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			modal.Exit()
+		}
+
+		return event
+	})
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
+
+// presetOptions converts the options form's data into a preset.
+func presetOptions(params map[string]interface{}) (rcfns.Preset, error) {
+	preset := rcfns.Preset{
+		Name:           strings.TrimSpace(params["Name"].(string)),
+		BwLimitFile:    strings.TrimSpace(params["Bandwidth Limit"].(string)),
+		CheckSum:       params["Checksum"].(bool),
+		IgnoreExisting: params["Ignore Existing"].(bool),
+		UpdateOlder:    params["Update"].(bool),
+		NoTraverse:     params["No Traverse"].(bool),
+		DryRun:         params["Dry Run"].(bool),
+	}
+
+	for _, option := range []struct {
+		label string
+		value *int
+	}{
+		{"Transfers", &preset.Transfers},
+		{"Checkers", &preset.Checkers},
+	} {
+		value := strings.TrimSpace(params[option.label].(string))
+		if value == "" {
+			continue
+		}
+
+		number, err := strconv.Atoi(value)
+		if err != nil || number <= 0 {
+			return rcfns.Preset{}, fmt.Errorf("%s should be a positive number", option.label)
+		}
+
+		*option.value = number
+	}
+
+	return preset, nil
+}
+
+// presetDescription returns a summary of the preset's options.
+func presetDescription(preset rcfns.Preset) string {
+	var desc []string
+
+	if preset.Transfers > 0 {
+		desc = append(desc, "transfers: "+strconv.Itoa(preset.Transfers))
+	}
+	if preset.Checkers > 0 {
+		desc = append(desc, "checkers: "+strconv.Itoa(preset.Checkers))
+	}
+	if preset.BwLimitFile != "" {
+		desc = append(desc, "bandwidth: "+preset.BwLimitFile)
+	}
+
+	for _, option := range []struct {
+		label string
+		set   bool
+	}{
+		{"checksum", preset.CheckSum},
+		{"ignore existing", preset.IgnoreExisting},
+		{"update", preset.UpdateOlder},
+		{"no traverse", preset.NoTraverse},
+		{"dry run", preset.DryRun},
+	} {
+		if option.set {
+			desc = append(desc, option.label)
+		}
+	}
+
+	return strings.Join(desc, ", ")
+}
+
+// presetNumber returns the number as form text, with zero as an empty value.
+func presetNumber(number int) string {
+	if number <= 0 {
+		return ""
+	}
+
+	return strconv.Itoa(number)
+}
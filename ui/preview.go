@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"code.cloudfoundry.org/bytefmt"
+	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// previewPages stores the offsets and the starting line numbers of the
+// pages which have been previewed.
+type previewPages struct {
+	offsets []int64
+	lines   []int
+
+	lock sync.Mutex
+}
+
+// showPreview shows the contents of the file under the cursor in a modal.
+// Text files are shown with line numbers, and binary files as a hex dump.
+func (p *Pane) showPreview() {
+	var pages previewPages
+
+	if !p.Lock.TryAcquire(1) {
+		return
+	}
+	defer p.Lock.Release(1)
+
+	_, item, err := p.getSelection()
+	if err != nil || item.IsDir {
+		return
+	}
+
+	modal := NewModal("preview", "Preview: "+tview.Escape(item.Name), false, true, 60, 150)
+	modal.TextView.SetWrap(false)
+
+	loadPage := func(page int) {
+		pages.lock.Lock()
+		defer pages.lock.Unlock()
+
+		if page < 0 || page >= len(pages.offsets) {
+			return
+		}
+
+		go p.startLoading("Loading preview for " + item.Name)
+		defer p.stopLoading()
+
+		preview, err := rcfns.ReadPreview(item, pages.offsets[page])
+		if err != nil {
+			ErrorMessage("Explorer", err)
+			return
+		}
+
+		if page == len(pages.offsets)-1 && preview.Next < preview.Total {
+			pages.offsets = append(pages.offsets, preview.Next)
+			pages.lines = append(pages.lines, pages.lines[page]+len(preview.Lines()))
+		}
+
+		App.QueueUpdateDraw(func() {
+			modal.TextView.Clear()
+
+			fmt.Fprintf(modal.TextView,
+				"[::b]Page %d/%s, bytes %d-%d of %s[-:-:-] ([::b]n[-:-:-] next, [::b]p[-:-:-] previous page)\n\n",
+				page+1, previewPageCount(preview.Total), preview.Offset, preview.Next,
+				bytefmt.ByteSize(uint64(preview.Total)),
+			)
+
+			if preview.Binary {
+				for _, line := range preview.HexDump() {
+					fmt.Fprintln(modal.TextView, tview.Escape(line))
+				}
+			} else {
+				lines := preview.Lines()
+				width := len(fmt.Sprint(pages.lines[page] + len(lines)))
+
+				for i, line := range lines {
+					fmt.Fprintf(modal.TextView, "[grey]%*d[-] %s\n",
+						width, pages.lines[page]+i+1, tview.Escape(strings.TrimSuffix(line, "\r")),
+					)
+				}
+			}
+
+			modal.TextView.ScrollToBeginning()
+		})
+	}
+
+	currentPage := 0
+
+	modal.TextView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			modal.Exit()
+		}
+
+		if event.Rune() != 'n' && event.Rune() != 'p' {
+			return event
+		}
+
+		if !pages.lock.TryLock() {
+			return event
+		}
+
+		switch event.Rune() {
+		case 'n':
+			if currentPage+1 < len(pages.offsets) {
+				currentPage++
+				go loadPage(currentPage)
+			}
+
+		case 'p':
+			if currentPage > 0 {
+				currentPage--
+				go loadPage(currentPage)
+			}
+		}
+
+		pages.lock.Unlock()
+
+		return event
+	})
+
+	pages.offsets = []int64{0}
+	pages.lines = []int{0}
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+
+	loadPage(0)
+}
+
+// previewPageCount returns the approximate number of pages for the file's size.
+func previewPageCount(size int64) string {
+	if size <= 0 {
+		return "1"
+	}
+
+	count := (size + rcfns.PreviewPageSize - 1) / rcfns.PreviewPageSize
+
+	if count > 1 {
+		return "~" + fmt.Sprint(count)
+	}
+
+	return fmt.Sprint(count)
+}
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fmt"
+
+	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// removeEmptyDirs finds the empty directories within the selected directories,
+// or within the current directory if no directories are selected, and shows
+// them before removing them.
+func (p *Pane) removeEmptyDirs() {
+	var dirs []rcfns.EmptyDirs
+	var targets []rcfns.ListItem
+	var count int
+
+	if !p.Lock.TryAcquire(1) {
+		return
+	}
+
+	if p.FS == "" {
+		p.Lock.Release(1)
+		return
+	}
+
+	if rcfns.IsLocal(p.FS) {
+		p.Lock.Release(1)
+		ErrorMessage("Explorer", fmt.Errorf("Empty directories cannot be removed from this machine"))
+		return
+	}
+
+	for _, item := range explorer.getSelectionsList() {
+		if item.IsDir && !rcfns.IsLocal(item.FS) {
+			targets = append(targets, item)
+		}
+	}
+
+	leaveRoot := targets == nil
+	if leaveRoot {
+		targets = append(targets, rcfns.ListItem{FS: p.FS, Path: p.Path, IsDir: true})
+	}
+
+	go p.startLoading("Finding empty directories")
+	for _, target := range targets {
+		emptyDirs, err := rcfns.FindEmptyDirs(target, leaveRoot)
+		if err != nil {
+			p.stopLoading()
+			p.Lock.Release(1)
+			ErrorMessage("Explorer", err)
+			return
+		}
+
+		if emptyDirs.Empty != nil {
+			dirs = append(dirs, emptyDirs)
+			count += len(emptyDirs.Empty)
+		}
+	}
+	p.stopLoading()
+	p.Lock.Release(1)
+
+	if dirs == nil {
+		InfoMessage("No empty directories found", false)
+		return
+	}
+
+	modal := NewModal("rmdirs", "Remove empty directories", false, false, count+10, 100)
+	modal.Table.SetSelectorWrap(false)
+	modal.Table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			modal.Exit()
+
+			rcfns.RemoveEmptyDirs(dirs)
+			go explorer.reloadPanes(true)
+
+			return nil
+
+		case tcell.KeyEscape:
+			modal.Exit()
+			return nil
+		}
+
+		return event
+	})
+
+	modal.Table.SetCell(0, 0, tview.NewTableCell(fmt.Sprintf(
+		"[::b]%d empty directories will be removed[-:-:-] (Enter to remove, Escape to cancel)", count,
+	)).
+		SetSelectable(false),
+	)
+
+	row := 1
+	for _, dir := range dirs {
+		for _, item := range dir.Empty {
+			modal.Table.SetCell(row, 0, tview.NewTableCell(tview.Escape(item.FS+item.Path+"/")).
+				SetExpansion(1).
+				SetTextColor(tcell.ColorBlue),
+			)
+
+			row++
+		}
+	}
+
+	modal.Table.Select(1, 0)
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
@@ -0,0 +1,246 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/darkhz/rclone-tui/rclone"
+	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// search shows a form to recursively search the pane's current directory.
+func (p *Pane) search() {
+	var modal *Modal
+
+	if p.FS == "" {
+		return
+	}
+
+	params := map[string]interface{}{
+		"Pattern":  "",
+		"Regex":    false,
+		"Min Size": "",
+		"Max Size": "",
+		"Min Age":  "",
+		"Max Age":  "",
+	}
+
+	setData := func(name string, data interface{}) {
+		params[name] = data
+	}
+
+	form := NewForm()
+	form.SetButtonsAlign(tview.AlignCenter)
+	form.AddFormItem(
+		GetFormInputField("Pattern", true, false, setData, func(label string) {}),
+	)
+	form.AddFormItem(
+		GetFormCheckBox("Regex", setData, func(label string) {}),
+	)
+	for _, label := range []string{"Min Size", "Max Size", "Min Age", "Max Age"} {
+		form.AddFormItem(
+			GetFormInputField(label, true, false, setData, func(label string) {}),
+		)
+	}
+	form.AddButton("Search", func() {
+		modal.Exit()
+
+		go p.searchResults(rcfns.SearchOptions{
+			Pattern: params["Pattern"].(string),
+			Regex:   params["Regex"].(bool),
+			MinSize: params["Min Size"].(string),
+			MaxSize: params["Max Size"].(string),
+			MinAge:  params["Min Age"].(string),
+			MaxAge:  params["Max Age"].(string),
+		})
+	})
+	form.AddButton("Cancel", func() {
+		modal.Exit()
+	})
+
+	modal = NewCustomModal("search_form", form, form.GetFormItemCount()+10, 100)
+	modal.Flex.SetTitle("[::bu]Search " + tview.Escape(p.FS+p.Path))
+	modal.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			modal.Exit()
+		}
+
+		return event
+	})
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
+
+// searchResults starts the search, and shows the matching entries as they are found.
+// Results can be selected for other operations, or opened in the pane.
+func (p *Pane) searchResults(opts rcfns.SearchOptions) {
+	var results []rcfns.ListItem
+
+	fs, path := p.FS, p.Path
+	status := "searching"
+
+	modal := NewModal("search_results", "Search results", false, false, 40, 120)
+	modal.Table.SetSelectorWrap(false)
+
+	setHeader := func() {
+		modal.Table.SetCell(0, 0, tview.NewTableCell(fmt.Sprintf(
+			"[::b]%d matches in %s[-:-:-] (%s)",
+			len(results), tview.Escape(fs+path), status,
+		)).
+			SetExpansion(1).
+			SetSelectable(false),
+		)
+	}
+
+	setRow := func(row int, item rcfns.ListItem) {
+		name := strings.TrimPrefix(strings.TrimPrefix(item.Path, path), "/")
+		if item.IsDir {
+			name += "/"
+		}
+
+		color := tcell.ColorWhite
+		if item.IsDir {
+			color = tcell.ColorBlue
+		}
+		if p.itemSelected(item, false) {
+			color = tcell.ColorOrange
+		}
+
+		modal.Table.SetCell(row, 0, tview.NewTableCell(tview.Escape(name)).
+			SetExpansion(1).
+			SetTextColor(color).
+			SetReference(item),
+		)
+		modal.Table.SetCell(row, 1, tview.NewTableCell(item.ISize).
+			SetAlign(tview.AlignRight).
+			SetTextColor(tcell.ColorGrey),
+		)
+		modal.Table.SetCell(row, 2, tview.NewTableCell(item.ModifiedTime).
+			SetTextColor(tcell.ColorGrey),
+		)
+	}
+
+	job, err := rcfns.Search(fs, path, opts, func(items []rcfns.ListItem) {
+		App.QueueUpdateDraw(func() {
+			for _, item := range items {
+				results = append(results, item)
+				setRow(len(results), item)
+			}
+
+			setHeader()
+		})
+	})
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	exit := func() {
+		modal.Exit()
+		job.Cancel()
+
+		for _, pane := range explorer.Panes {
+			row, _ := pane.View.GetSelection()
+
+			pane.viewList(pane.list)
+			pane.View.Select(row, 0)
+		}
+	}
+
+	modal.Table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			row, _ := modal.Table.GetSelection()
+
+			item, ok := modal.Table.GetCell(row, 0).GetReference().(rcfns.ListItem)
+			if !ok {
+				return nil
+			}
+
+			exit()
+			go p.openSearchResult(item)
+
+			return nil
+
+		case tcell.KeyEscape:
+			exit()
+			return nil
+		}
+
+		switch event.Rune() {
+		case ' ':
+			row, _ := modal.Table.GetSelection()
+
+			item, ok := modal.Table.GetCell(row, 0).GetReference().(rcfns.ListItem)
+			if !ok {
+				break
+			}
+
+			p.itemSelected(item, false, row)
+			setRow(row, item)
+
+			if row+1 < modal.Table.GetRowCount() {
+				modal.Table.Select(row+1, 0)
+			}
+
+		case 'a':
+			for row, item := range results {
+				p.itemSelected(item, true)
+				setRow(row+1, item)
+			}
+		}
+
+		return event
+	})
+
+	setHeader()
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+
+	go func() {
+		jobInfo, _ := rclone.GetJobReply(job)
+
+		App.QueueUpdateDraw(func() {
+			switch {
+			case job.Context.Err() != nil:
+				status = "cancelled"
+
+			case jobInfo.Error != "":
+				status = "[red]" + tview.Escape(jobInfo.Error) + "[-]"
+
+			default:
+				status = "done"
+			}
+
+			setHeader()
+		})
+	}()
+}
+
+// openSearchResult lists the directory which contains the search result,
+// and moves the cursor to the result.
+func (p *Pane) openSearchResult(item rcfns.ListItem) {
+	dir := filepath.ToSlash(filepath.Dir(item.Path))
+	if dir == "." {
+		dir = ""
+	}
+
+	p.List(rcfns.ListItem{FS: item.FS, Path: dir})
+
+	App.QueueUpdateDraw(func() {
+		for row, listItem := range p.list.Items {
+			if listItem.Name == item.Name {
+				p.View.Select(row, 0)
+				break
+			}
+		}
+	})
+}
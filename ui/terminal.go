@@ -0,0 +1,85 @@
+//go:build !windows
+// +build !windows
+
+package ui
+
+import (
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// screenTty stores the tty which the screen draws to. Writes to the tty
+// are serialized, so that escape sequences which are written to the
+// terminal do not interleave with the screen's output.
+type screenTty struct {
+	tcell.Tty
+
+	started bool
+	lock    sync.Mutex
+}
+
+var terminal *screenTty
+
+// setupScreen sets up the application's screen with a tty,
+// which is shared with writeTerminal.
+func setupScreen() {
+	tty, err := tcell.NewDevTty()
+	if err != nil {
+		return
+	}
+
+	screenTty := &screenTty{Tty: tty}
+
+	screen, err := tcell.NewTerminfoScreenFromTty(screenTty)
+	if err != nil {
+		return
+	}
+
+	terminal = screenTty
+	App.SetScreen(screen)
+}
+
+// writeTerminal writes the escape sequence to the terminal, between the
+// screen's draws. The sequence is dropped if the screen is suspended.
+func writeTerminal(sequence string) {
+	if terminal == nil {
+		return
+	}
+
+	terminal.lock.Lock()
+	defer terminal.lock.Unlock()
+
+	if terminal.started {
+		terminal.Tty.Write([]byte(sequence))
+	}
+}
+
+// Start starts the tty.
+func (s *screenTty) Start() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	err := s.Tty.Start()
+	s.started = err == nil
+
+	return err
+}
+
+// Stop stops the tty.
+func (s *screenTty) Stop() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.started = false
+
+	return s.Tty.Stop()
+}
+
+// Write writes the screen's output to the tty.
+func (s *screenTty) Write(b []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.Tty.Write(b)
+}
@@ -0,0 +1,22 @@
+//go:build windows
+// +build windows
+
+package ui
+
+import (
+	"fmt"
+	"os"
+)
+
+// setupScreen is disabled in Windows, where the default console screen is used.
+func setupScreen() {
+}
+
+// writeTerminal writes the escape sequence to the terminal. The application
+// is locked while writing, so that the sequence is not written during a draw.
+func writeTerminal(sequence string) {
+	App.Lock()
+	defer App.Unlock()
+
+	fmt.Fprint(os.Stdout, sequence)
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// setTier shows the storage tiers supported by the remote, and changes the
+// storage tier of the selected items, or the item under the cursor if no
+// items are selected.
+func (p *Pane) setTier() {
+	items := explorer.getSelectionsList()
+	if len(items) == 0 {
+		_, item, err := p.getSelection()
+		if err != nil {
+			return
+		}
+
+		items = append(items, item)
+	}
+
+	fs := items[0].FS
+	for _, item := range items {
+		if item.FS != fs {
+			ErrorMessage("Explorer", fmt.Errorf("Items must be within the same remote"))
+			return
+		}
+	}
+
+	if rcfns.IsLocal(fs) {
+		ErrorMessage("Explorer", fmt.Errorf("Storage tiers cannot be changed on this machine"))
+		return
+	}
+
+	if !p.Lock.TryAcquire(1) {
+		return
+	}
+
+	go p.startLoading("Loading storage tiers for " + fs)
+	tiers, err := rcfns.StorageTiers(p.ID, fs)
+	p.stopLoading()
+	p.Lock.Release(1)
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	if tiers == nil {
+		tier := strings.TrimSpace(SetInput("Storage tier:", struct{}{}))
+		if tier == "" {
+			return
+		}
+
+		changeTier(items, tier)
+
+		return
+	}
+
+	modal := NewModal("tier", "Change storage tier", false, false, len(tiers)+10, 60)
+	modal.Table.SetSelectorWrap(false)
+	modal.Table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			row, _ := modal.Table.GetSelection()
+
+			tier, ok := modal.Table.GetCell(row, 0).GetReference().(string)
+			if !ok {
+				break
+			}
+
+			modal.Exit()
+			go changeTier(items, tier)
+
+			return nil
+
+		case tcell.KeyEscape:
+			modal.Exit()
+			return nil
+		}
+
+		return event
+	})
+
+	modal.Table.SetCell(0, 0, tview.NewTableCell(
+		fmt.Sprintf("[::b]Select tier for %d item(s)", len(items)),
+	).
+		SetSelectable(false),
+	)
+
+	for row, tier := range tiers {
+		modal.Table.SetCell(row+1, 0, tview.NewTableCell(tier).
+			SetExpansion(1).
+			SetReference(tier),
+		)
+	}
+
+	modal.Table.Select(1, 0)
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
+
+// changeTier changes the storage tier of the items if confirmed.
+func changeTier(items []rcfns.ListItem, tier string) {
+	if !ConfirmInput(fmt.Sprintf("Change storage tier of %d item(s) to %s? (y/n)", len(items), tier)) {
+		return
+	}
+
+	rcfns.SetTier(items, tier)
+	go explorer.reloadPanes(true)
+}
+
+// toggleTiers shows or hides the storage tier column within the panes.
+func (e *ExplorerUI) toggleTiers() {
+	e.showTiers = !e.showTiers
+
+	for _, pane := range e.Panes {
+		if pane.FS == "" {
+			continue
+		}
+
+		row, _ := pane.View.GetSelection()
+
+		pane.viewList(pane.list)
+		pane.View.Select(row, 0)
+	}
+}
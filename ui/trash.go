@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/bytefmt"
+	"github.com/darkhz/rclone-tui/rclone"
+	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// showTrash shows the trashed items within the pane's current directory, which can be
+// restored, and the option to clean up the remote's trash. If the remote does not support
+// restoring trashed items, the trash is cleaned up directly after confirmation.
+func (p *Pane) showTrash() {
+	if !p.Lock.TryAcquire(1) {
+		return
+	}
+
+	if p.FS == "" {
+		p.Lock.Release(1)
+		return
+	}
+
+	if rcfns.IsLocal(p.FS) {
+		p.Lock.Release(1)
+		ErrorMessage("Explorer", fmt.Errorf("Trash cannot be cleaned up on this machine"))
+		return
+	}
+
+	var items []rcfns.ListItem
+
+	fs, path := p.FS, p.Path
+
+	go p.startLoading("Loading trash information for " + fs)
+	trash, err := rcfns.TrashInfo(p.ID, fs)
+	if err == nil && trash.CanRestore {
+		items, err = rcfns.ListTrash(rclone.GetClientContext(), fs, path)
+	}
+	p.stopLoading()
+	p.Lock.Release(1)
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	switch {
+	case trash.CanRestore:
+		p.showTrashModal(fs, path, trash, items)
+
+	case !trash.CanCleanup:
+		ErrorMessage("Explorer", fmt.Errorf("%s: Cleaning up the trash is not supported", fs))
+
+	default:
+		cleanupTrash(fs, trash)
+	}
+}
+
+// showTrashModal shows the trashed items within the directory.
+func (p *Pane) showTrashModal(fs, path string, trash rcfns.TrashDetail, items []rcfns.ListItem) {
+	modal := NewModal("trash", "Trash", false, false, len(items)+10, 100)
+	modal.Table.SetSelectorWrap(false)
+
+	modal.Table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			modal.Exit()
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'r':
+			if items == nil {
+				break
+			}
+
+			modal.Exit()
+
+			go func() {
+				if ConfirmInput(fmt.Sprintf("Restore %d trashed items in %s? (y/n)", len(items), fs+path)) {
+					rcfns.RestoreTrash(fs, path)
+				}
+			}()
+
+		case 'c':
+			if !trash.CanCleanup {
+				break
+			}
+
+			modal.Exit()
+			go cleanupTrash(fs, trash)
+		}
+
+		return event
+	})
+
+	keys := "[::b]r[-:-:-] restore all"
+	if trash.CanCleanup {
+		keys += ", [::b]c[-:-:-] clean up trash"
+	}
+
+	modal.Table.SetCell(0, 0, tview.NewTableCell(fmt.Sprintf(
+		"[::b]%d trashed items in %s[-:-:-] (trash size: %s), %s",
+		len(items), tview.Escape(fs+path), trashSize(trash), keys,
+	)).
+		SetSelectable(false),
+	)
+
+	for row, item := range items {
+		name, color := item.Name, tcell.ColorWhite
+		if item.IsDir {
+			name, color = name+"/", tcell.ColorBlue
+		}
+
+		modal.Table.SetCell(row+1, 0, tview.NewTableCell(tview.Escape(name)).
+			SetExpansion(1).
+			SetTextColor(color),
+		)
+		modal.Table.SetCell(row+1, 1, tview.NewTableCell(item.ISize).
+			SetAlign(tview.AlignRight).
+			SetTextColor(tcell.ColorGrey),
+		)
+		modal.Table.SetCell(row+1, 2, tview.NewTableCell(item.ModifiedTime).
+			SetTextColor(tcell.ColorGrey),
+		)
+	}
+
+	modal.Table.Select(1, 0)
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
+
+// cleanupTrash permanently deletes the remote's trashed items after confirmation.
+func cleanupTrash(fs string, trash rcfns.TrashDetail) {
+	if !ConfirmInput("Permanently delete all trashed items in " + fs + " (size: " + trashSize(trash) + ")? (y/n)") {
+		return
+	}
+
+	rcfns.CleanupTrash(fs)
+}
+
+// trashSize returns the size of the trashed items.
+func trashSize(trash rcfns.TrashDetail) string {
+	if trash.Size < 0 {
+		return "unknown"
+	}
+
+	return bytefmt.ByteSize(uint64(trash.Size))
+}
@@ -49,6 +49,11 @@ func SetupUI() {
 	}
 
 	go JobMonitor()
+	go NotifyMonitor()
+	go startScheduler()
+	go loadFilters()
+	go loadPresets()
+	go loadLinks()
 
 	App.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Key() {
@@ -108,6 +113,8 @@ func SetupUI() {
 		resizeModal()
 	})
 
+	setupScreen()
+
 	if err := App.SetRoot(uiLayout, true).SetFocus(flex).Run(); err != nil {
 		panic(err)
 	}
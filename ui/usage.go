@@ -0,0 +1,327 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"code.cloudfoundry.org/bytefmt"
+	"github.com/darkhz/rclone-tui/rclone"
+	rcfns "github.com/darkhz/rclone-tui/rclone/operations"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// usageBarWidth is the width of the percentage bars in the disk usage analyzer.
+const usageBarWidth = 20
+
+// showDiskUsage recursively analyzes the disk usage of the pane's current directory,
+// and shows the directory tree sorted by size. The selected entries within the tree can
+// be deleted, or moved to the other pane's directory.
+func (p *Pane) showDiskUsage() {
+	if !p.Lock.TryAcquire(1) {
+		return
+	}
+
+	if p.FS == "" {
+		p.Lock.Release(1)
+		return
+	}
+
+	go p.startLoading("Analyzing disk usage of " + p.FS + p.Path)
+	root, err := rcfns.DiskUsage(p.FS, p.Path)
+	p.stopLoading()
+	p.Lock.Release(1)
+	if err != nil {
+		ErrorMessage("Explorer", err)
+		return
+	}
+
+	p.showUsageModal(root, make(map[*rcfns.UsageEntry]struct{}))
+}
+
+// showUsageModal shows the entries within the directory, and the percentage of the
+// directory's size that each entry uses.
+func (p *Pane) showUsageModal(dir *rcfns.UsageEntry, selected map[*rcfns.UsageEntry]struct{}) {
+	modal := NewModal("disk_usage", "Disk usage", false, false, 40, 120)
+	modal.Table.SetSelectorWrap(false)
+
+	update := func(row int) {
+		modal.Table.Clear()
+
+		modal.Table.SetCell(0, 0, tview.NewTableCell(fmt.Sprintf(
+			"[::b]%s[-:-:-] (%s, %d objects, %d selected)",
+			tview.Escape(dir.Item.FS+dir.Item.Path), usageSize(dir.Size), dir.Count, len(selected),
+		)).
+			SetExpansion(1).
+			SetSelectable(false),
+		)
+
+		offset := 1
+		if dir.Parent != nil {
+			modal.Table.SetCell(1, 0, tview.NewTableCell("..").
+				SetReference(dir.Parent),
+			)
+
+			offset++
+		}
+
+		for i, entry := range dir.Entries {
+			var percent float64
+
+			if dir.Size > 0 {
+				percent = float64(entry.Size) * 100 / float64(dir.Size)
+			}
+
+			mark, color := " ", tcell.ColorWhite
+			if _, ok := selected[entry]; ok {
+				mark, color = "*", tcell.ColorYellow
+			}
+
+			name := entry.Item.Name
+			if entry.Item.IsDir {
+				name += "/"
+			}
+
+			filled := int(percent * usageBarWidth / 100)
+
+			modal.Table.SetCell(i+offset, 0, tview.NewTableCell(mark+" "+tview.Escape(name)).
+				SetExpansion(1).
+				SetTextColor(color).
+				SetReference(entry),
+			)
+			modal.Table.SetCell(i+offset, 1, tview.NewTableCell(fmt.Sprintf(
+				"[green]%s[grey]%s[-] %5.1f%%",
+				strings.Repeat("█", filled), strings.Repeat("░", usageBarWidth-filled), percent,
+			)))
+			modal.Table.SetCell(i+offset, 2, tview.NewTableCell(usageSize(entry.Size)).
+				SetAlign(tview.AlignRight),
+			)
+			modal.Table.SetCell(i+offset, 3, tview.NewTableCell(fmt.Sprint(entry.Count)).
+				SetAlign(tview.AlignRight).
+				SetTextColor(tcell.ColorGrey),
+			)
+		}
+
+		if row >= modal.Table.GetRowCount() {
+			row = modal.Table.GetRowCount() - 1
+		}
+		if row < 1 {
+			row = 1
+		}
+
+		modal.Table.Select(row, 0)
+		modal.Table.ScrollToBeginning()
+	}
+
+	getEntry := func() *rcfns.UsageEntry {
+		row, _ := modal.Table.GetSelection()
+
+		entry, _ := modal.Table.GetCell(row, 0).GetReference().(*rcfns.UsageEntry)
+
+		return entry
+	}
+
+	changeDir := func(entry *rcfns.UsageEntry) {
+		if entry == nil || !entry.Item.IsDir {
+			return
+		}
+
+		row := 1
+		if entry == dir.Parent {
+			for i, parentEntry := range entry.Entries {
+				if parentEntry == dir {
+					row = i + 1
+					if entry.Parent != nil {
+						row++
+					}
+
+					break
+				}
+			}
+		}
+
+		dir = entry
+		update(row)
+	}
+
+	modal.Table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter, tcell.KeyRight:
+			changeDir(getEntry())
+			return nil
+
+		case tcell.KeyLeft, tcell.KeyBackspace, tcell.KeyBackspace2:
+			changeDir(dir.Parent)
+			return nil
+
+		case tcell.KeyEscape:
+			modal.Exit()
+			return nil
+		}
+
+		switch event.Rune() {
+		case ' ':
+			entry := getEntry()
+			if entry == nil || entry == dir.Parent {
+				break
+			}
+
+			if _, ok := selected[entry]; ok {
+				delete(selected, entry)
+			} else {
+				selected[entry] = struct{}{}
+			}
+
+			row, _ := modal.Table.GetSelection()
+			update(row + 1)
+
+		case 'd', 'm':
+			entries := usageEntries(selected, getEntry(), dir)
+			if entries == nil {
+				break
+			}
+
+			modal.Exit()
+			go p.usageOperation(event.Rune(), dir, entries, selected)
+		}
+
+		return event
+	})
+
+	update(1)
+
+	App.QueueUpdateDraw(func() {
+		modal.Show()
+	})
+}
+
+// usageOperation deletes the entries, or moves them to the other pane's directory.
+// Once the operation has finished, the entries which were deleted or moved are
+// removed from the tree, and the tree is shown again.
+func (p *Pane) usageOperation(
+	key rune, dir *rcfns.UsageEntry,
+	entries []*rcfns.UsageEntry, selected map[*rcfns.UsageEntry]struct{},
+) {
+	var job *rclone.Job
+	var items []rcfns.ListItem
+
+	for _, entry := range entries {
+		items = append(items, entry.Item)
+	}
+
+	defer func() {
+		p.showUsageModal(dir, selected)
+	}()
+
+	if rcfns.IsLocal(dir.Item.FS) {
+		ErrorMessage("Explorer", fmt.Errorf("Items can only be copied to or from this machine"))
+		return
+	}
+
+	switch key {
+	case 'd':
+		if !ConfirmInput(fmt.Sprintf("Delete %d items? (y/n)", len(items))) {
+			return
+		}
+
+		job = rcfns.Delete(items)
+
+	case 'm':
+		dstPane := explorer.otherPane(p)
+		if dstPane.FS == "" || rcfns.IsLocal(dstPane.FS) {
+			ErrorMessage("Explorer", fmt.Errorf("Select a remote in the other pane to move the items to"))
+			return
+		}
+
+		if !ConfirmInput(fmt.Sprintf("Move %d items to %s? (y/n)", len(items), dstPane.FS+dstPane.Path)) {
+			return
+		}
+
+		job = rcfns.Move(items, dstPane.FS, dstPane.Path)
+	}
+
+	if job == nil {
+		return
+	}
+
+	go p.startLoading(fmt.Sprintf("%s %d items", job.Description, len(items)))
+	_, err := rclone.GetJobReply(job)
+	p.stopLoading()
+
+	removed := make(map[*rcfns.UsageEntry]struct{})
+	for _, entry := range entries {
+		if err != nil {
+			exists, statErr := rcfns.Exists(rclone.GetClientContext(), entry.Item.FS, entry.Item.Path)
+			if statErr != nil || exists {
+				continue
+			}
+		}
+
+		entry.Remove()
+		removed[entry] = struct{}{}
+	}
+
+	for entry := range selected {
+		if usageEntryRemoved(entry, removed) != nil {
+			delete(selected, entry)
+		}
+	}
+
+	for usageEntryRemoved(dir, removed) != nil {
+		dir = usageEntryRemoved(dir, removed).Parent
+	}
+
+	go explorer.reloadPanes(true)
+}
+
+// usageEntries returns the selected entries within the tree, or the entry
+// under the cursor if no entries are selected. Entries within selected
+// directories are skipped.
+func usageEntries(
+	selected map[*rcfns.UsageEntry]struct{},
+	current, dir *rcfns.UsageEntry,
+) []*rcfns.UsageEntry {
+	var entries []*rcfns.UsageEntry
+
+	for entry := range selected {
+		var parentSelected bool
+
+		for parent := entry.Parent; parent != nil; parent = parent.Parent {
+			if _, ok := selected[parent]; ok {
+				parentSelected = true
+				break
+			}
+		}
+
+		if !parentSelected {
+			entries = append(entries, entry)
+		}
+	}
+
+	if entries == nil && current != nil && current != dir.Parent {
+		entries = append(entries, current)
+	}
+
+	return entries
+}
+
+// usageEntryRemoved returns the removed entry which is either the entry itself or
+// one of its parents, or nil if the entry has not been removed.
+func usageEntryRemoved(entry *rcfns.UsageEntry, removed map[*rcfns.UsageEntry]struct{}) *rcfns.UsageEntry {
+	for ; entry != nil; entry = entry.Parent {
+		if _, ok := removed[entry]; ok {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// usageSize returns the formatted size of a disk usage entry.
+func usageSize(size int64) string {
+	if size <= 0 {
+		return "0B"
+	}
+
+	return bytefmt.ByteSize(uint64(size))
+}
@@ -174,9 +174,12 @@ func InitViewByName(name string) {
 
 // updateIndicators updates the connectivity/job count indicators.
 func updateIndicators() {
+	wasConnected := false
+	events := rclone.SubscribeJobEvents(0, true)
+
 	for {
 		select {
-		case info := <-jobIndicator:
+		case info := <-events.Events():
 			App.QueueUpdateDraw(func() {
 				var text string
 				var color tcell.Color
@@ -199,6 +202,18 @@ func updateIndicators() {
 			})
 
 		case connected := <-rclone.PollConnection(false):
+			if wasConnected && !connected {
+				host := ""
+				if client, err := rclone.GetCurrentClient(); err == nil {
+					host = client.Hostname()
+				}
+
+				Notify(EventConnectionLost, "Connection lost to "+host, map[string]string{
+					"Host": host,
+				})
+			}
+			wasConnected = connected
+
 			App.QueueUpdateDraw(func() {
 				var color tcell.Color
 